@@ -0,0 +1,151 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// AssociationType is the kind of link between two CRM objects, such as a deal and a contact. Associations go in one
+// direction, but Hubspot creates the reverse association automatically, so a deal associated with a contact shows up
+// when asking for the contact's deals.
+type AssociationType int
+
+// The association types Hubspot defines between the standard CRM objects
+const (
+	AssociationContactToCompany    AssociationType = 1
+	AssociationCompanyToContact    AssociationType = 2
+	AssociationDealToContact       AssociationType = 3
+	AssociationContactToDeal       AssociationType = 4
+	AssociationDealToCompany       AssociationType = 5
+	AssociationCompanyToDeal       AssociationType = 6
+	AssociationCompanyToEngagement AssociationType = 7
+	AssociationEngagementToCompany AssociationType = 8
+	AssociationContactToEngagement AssociationType = 9
+	AssociationEngagementToContact AssociationType = 10
+	AssociationDealToEngagement    AssociationType = 11
+	AssociationEngagementToDeal    AssociationType = 12
+	AssociationContactToTicket     AssociationType = 15
+	AssociationTicketToContact     AssociationType = 16
+	AssociationTicketToEngagement  AssociationType = 17
+	AssociationEngagementToTicket  AssociationType = 18
+	AssociationCompanyToTicket     AssociationType = 25
+	AssociationTicketToCompany     AssociationType = 26
+	AssociationDealToTicket        AssociationType = 27
+	AssociationTicketToDeal        AssociationType = 28
+)
+
+// associationPageSize is the most associated IDs Hubspot will return in one page
+const associationPageSize = 100
+
+// associationRequest is the body to create or remove an association
+type associationRequest struct {
+	FromObjectID int64           `json:"fromObjectId"`
+	ToObjectID   int64           `json:"toObjectId"`
+	Category     string          `json:"category"`
+	DefinitionID AssociationType `json:"definitionId"`
+}
+
+// associationListResponse is a single page of associated IDs
+type associationListResponse struct {
+	Results []int64 `json:"results"`
+	HasMore bool    `json:"hasMore"`
+	Offset  int64   `json:"offset"`
+}
+
+// Associate links two CRM objects, such as a deal and a contact. Associating objects that are already associated is
+// not an error.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/crm-associations/associate-objects
+func (c *Client) Associate(ctx context.Context, fromID, toID int64, associationType AssociationType) error {
+	return c.changeAssociation(ctx, EndpointCreateAssociation, fromID, toID, associationType, CodeAssociationCouldNotBeCreated)
+}
+
+// Associate calls Client.Associate on the DefaultClient with a background context
+func Associate(fromID, toID int64, associationType AssociationType) error {
+	return DefaultClient().Associate(context.Background(), fromID, toID, associationType)
+}
+
+// RemoveAssociation removes the link between two CRM objects
+//
+// API Doc: https://developers.hubspot.com/docs/methods/crm-associations/delete-association
+func (c *Client) RemoveAssociation(ctx context.Context, fromID, toID int64, associationType AssociationType) error {
+	return c.changeAssociation(ctx, EndpointRemoveAssociation, fromID, toID, associationType, CodeAssociationCouldNotBeRemoved)
+}
+
+// RemoveAssociation calls Client.RemoveAssociation on the DefaultClient with a background context
+func RemoveAssociation(fromID, toID int64, associationType AssociationType) error {
+	return DefaultClient().RemoveAssociation(context.Background(), fromID, toID, associationType)
+}
+
+// GetAssociatedIDs gets the IDs of every object associated with the object, such as the VIDs of a deal's contacts when
+// using AssociationDealToContact
+//
+// API Doc: https://developers.hubspot.com/docs/methods/crm-associations/get-associations
+func (c *Client) GetAssociatedIDs(ctx context.Context, fromID int64, associationType AssociationType) ([]int64, error) {
+	ids := []int64{}
+	if fromID == 0 {
+		return ids, associationIDZeroError()
+	}
+	var offset int64
+	for {
+		query := url.Values{}
+		query.Set("limit", fmt.Sprintf("%d", associationPageSize))
+		if offset != 0 {
+			query.Set("offset", fmt.Sprintf("%d", offset))
+		}
+		result := associationListResponse{}
+		_, err := c.prepareCall(ctx, EndpointGetAssociations, map[string]string{
+			":objectID":     fmt.Sprintf("%d", fromID),
+			":definitionID": fmt.Sprintf("%d", associationType),
+		}, query, &result)
+		if err != nil {
+			if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+				apiErr.SystemCode = CodeAssociationCouldNotBeListed
+				return ids, apiErr
+			}
+			return ids, err
+		}
+		ids = append(ids, result.Results...)
+		if !result.HasMore || result.Offset == offset {
+			return ids, nil
+		}
+		offset = result.Offset
+	}
+}
+
+// GetAssociatedIDs calls Client.GetAssociatedIDs on the DefaultClient with a background context
+func GetAssociatedIDs(fromID int64, associationType AssociationType) ([]int64, error) {
+	return DefaultClient().GetAssociatedIDs(context.Background(), fromID, associationType)
+}
+
+// changeAssociation creates or removes an association
+func (c *Client) changeAssociation(ctx context.Context, endpoint string, fromID, toID int64, associationType AssociationType, failureCode string) error {
+	if fromID == 0 || toID == 0 {
+		return associationIDZeroError()
+	}
+	_, err := c.prepareCall(ctx, endpoint, map[string]string{}, associationRequest{
+		FromObjectID: fromID,
+		ToObjectID:   toID,
+		Category:     "HUBSPOT_DEFINED",
+		DefinitionID: associationType,
+	}, nil)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK {
+			apiErr.SystemCode = failureCode
+			return apiErr
+		}
+	}
+	return err
+}
+
+// associationIDZeroError is returned when one of the objects in an association is missing its ID
+func associationIDZeroError() APIError {
+	return APIError{
+		HTTPCode:   http.StatusBadRequest,
+		SystemCode: CodeAssociationIDZero,
+		Message:    "the IDs of both objects in an association must be set",
+		Body:       nil,
+	}
+}
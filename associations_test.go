@@ -0,0 +1,62 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssociations(t *testing.T) {
+	status := http.StatusNoContent
+	var path string
+	sent := associationRequest{}
+	offsets := []string{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if r.Method == http.MethodGet {
+			offsets = append(offsets, r.URL.Query().Get("offset"))
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Query().Get("offset") == "" {
+				fmt.Fprint(w, `{"results": [1, 2], "hasMore": true, "offset": 2}`)
+				return
+			}
+			fmt.Fprint(w, `{"results": [3], "hasMore": false, "offset": 3}`)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(status)
+	})
+	defer server.Close()
+
+	err := client.Associate(context.Background(), 0, 42, AssociationDealToContact)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeAssociationIDZero, err.(APIError).SystemCode)
+	assert.Equal(t, "", path)
+
+	require.Nil(t, client.Associate(context.Background(), 7, 42, AssociationDealToContact))
+	assert.Equal(t, "/crm-associations/v1/associations", path)
+	assert.Equal(t, associationRequest{FromObjectID: 7, ToObjectID: 42, Category: "HUBSPOT_DEFINED", DefinitionID: 3}, sent)
+
+	require.Nil(t, client.RemoveAssociation(context.Background(), 7, 42, AssociationDealToCompany))
+	assert.Equal(t, "/crm-associations/v1/associations/delete", path)
+	assert.Equal(t, AssociationType(5), sent.DefinitionID)
+
+	status = http.StatusBadRequest
+	err = client.Associate(context.Background(), 7, 42, AssociationDealToContact)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeAssociationCouldNotBeCreated, err.(APIError).SystemCode)
+	err = client.RemoveAssociation(context.Background(), 7, 42, AssociationDealToContact)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeAssociationCouldNotBeRemoved, err.(APIError).SystemCode)
+
+	ids, err := client.GetAssociatedIDs(context.Background(), 7, AssociationDealToContact)
+	require.Nil(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.Equal(t, "/crm-associations/v1/associations/7/HUBSPOT_DEFINED/3", path)
+	assert.Equal(t, []string{"", "2"}, offsets)
+}
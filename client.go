@@ -1,54 +1,125 @@
 package hubspot
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-resty/resty"
 )
 
-// APIReturn represents a successful API return value, with the HTTPCode representing the exact returned HTTP Code and the Body
-// representing the returned body
+// APIReturn represents a successful API return value, with the HTTPCode representing the exact returned HTTP Code and Raw
+// holding the undecoded body. The decoded body is written to the result passed in to the call.
 type APIReturn struct {
 	HTTPCode int
-	Body     interface{}
+	Raw      json.RawMessage
 }
 
-// APIError is the error struct containing additional information regarding the error that occurred. The HTTPCode is the returned value from
-// Hubspot OR a 400 if there was an error prior to calling Hubspot. The SystemCode is a constant and you can check systemCodes.go for more information.
-//
-type APIError struct {
-	HTTPCode   int
-	SystemCode string
-	Message    string
-	Body       *map[string]interface{}
+// Client talks to a single Hubspot portal. Each Client owns its own configuration, HTTP client, oAuth token and endpoint
+// table, so more than one portal can be used from the same process. The package-level functions use the DefaultClient.
+type Client struct {
+	config    *ConfigStruct
+	http      *resty.Client
+	endpoints map[string]endpoint
+	// defaultLogger is used when the config does not specify a Logger
+	defaultLogger Logger
+
+	token      OAuthToken
+	tokenMutex sync.Mutex
+
+	tokenSecrets []string
+	secretsMutex sync.Mutex
+
+	limiters      map[AuthMode]*rateLimiter
+	limitersMutex sync.Mutex
+}
+
+var (
+	defaultClient      *Client
+	defaultClientMutex sync.Mutex
+)
+
+// NewClient creates a new Client using the passed in configuration. If config is nil, the configuration is read from the
+// environment in the same way as ConfigSetup, but the global Config is left untouched.
+func NewClient(config *ConfigStruct) *Client {
+	if config == nil {
+		config = configFromEnvironment()
+	}
+	if !strings.HasSuffix(config.RootURL, "/") {
+		config.RootURL += "/"
+	}
+	if config.HubspotOAuthAuthorizeURL == "" {
+		config.HubspotOAuthAuthorizeURL = defaultOAuthAuthorizeURL
+	}
+
+	// every client gets its own copy of the endpoint table
+	clientEndpoints := make(map[string]endpoint, len(endpoints))
+	for k, v := range endpoints {
+		clientEndpoints[k] = v
+	}
+
+	return &Client{
+		config:        config,
+		http:          resty.New(),
+		endpoints:     clientEndpoints,
+		defaultLogger: newDefaultLogger(),
+	}
+}
+
+// DefaultClient returns the Client used by the package-level functions. It is bound to the global Config and follows it
+// if ConfigSetup is called again. If the global Config has not been set up yet, it is read from the environment now.
+// Nothing is fetched from Hubspot until the first call is made.
+func DefaultClient() *Client {
+	defaultClientMutex.Lock()
+	defer defaultClientMutex.Unlock()
+	if Config == nil {
+		ConfigSetup()
+	}
+	if defaultClient == nil {
+		defaultClient = NewClient(Config)
+	}
+	if defaultClient.config != Config {
+		defaultClient.config = Config
+	}
+	return defaultClient
 }
 
-func (e APIError) Error() string {
-	return e.Message
+// Config returns the configuration the client was created with
+func (c *Client) Config() *ConfigStruct {
+	return c.config
 }
 
-// prepareCall is the main entry point into the underlying HTTP req	uest generation and is used to actually prepare
-// calls to the API. Mocking is handled here as well.
-func prepareCall(endpoint string, pathParams map[string]string, data interface{}) (ret *APIReturn, err error) {
+// prepareCall is the main entry point into the underlying HTTP request generation and is used to actually prepare
+// calls to the API. Mocking is handled here as well. The context is passed down to the HTTP request, so cancelling it
+// or letting its deadline pass aborts the call. If result is not nil, the response body is decoded into it; pass a
+// *json.RawMessage to keep the body undecoded.
+func (c *Client) prepareCall(ctx context.Context, endpoint string, pathParams map[string]string, data interface{}, result interface{}) (ret *APIReturn, err error) {
 	// first, find the endpoint
-	info, infoFound := endpoints[endpoint]
+	info, infoFound := c.endpoints[endpoint]
 	if !infoFound {
 		return nil, APIError{
 			HTTPCode:   http.StatusBadRequest,
-			SystemCode: "request_error_endpoint_not_found",
+			SystemCode: CodeRequestEndpointNotFound,
 			Message:    "Could not find that endpoint",
 		}
 	}
 
 	// if the oauth is required but not provided, then we just return the mocked data
-	if (info.RequireOAuth && Config.HubSpotOAuthRefreshToken == "") || (Config.HubspotApplicationID == "test" && info.MockGood != nil) {
-		return &APIReturn{
+	if (info.RequireOAuth && c.config.HubSpotOAuthRefreshToken == "" && c.config.TokenStore == nil) || (c.config.HubspotApplicationID == "test" && info.MockGood != nil) {
+		raw := json.RawMessage{}
+		if info.MockGood != nil {
+			raw, _ = json.Marshal(info.MockGood)
+		}
+		ret = &APIReturn{
 			HTTPCode: http.StatusOK,
-			Body:     info.MockGood,
-		}, nil
+			Raw:      raw,
+		}
+		return ret, decodeResult(ret, result)
 	}
 
 	parsedPath := info.Path
@@ -58,114 +129,206 @@ func prepareCall(endpoint string, pathParams map[string]string, data interface{}
 	}
 
 	// we always replace application id if it is there
-	parsedPath = strings.Replace(parsedPath, ":applicationID", Config.HubspotApplicationID, -1)
+	parsedPath = strings.Replace(parsedPath, ":applicationID", c.config.HubspotApplicationID, -1)
+
+	ret, err = c.makeCall(ctx, info.Method, parsedPath, data, info.RequireOAuth)
+	if err != nil {
+		return nil, err
+	}
+	return ret, decodeResult(ret, result)
+}
 
-	return makeCall(info.Method, parsedPath, data, info.RequireOAuth)
+// decodeResult decodes the body of a successful call into the result. A body that does not match the result returns an
+// APIError rather than leaving the result half filled in silently.
+func decodeResult(ret *APIReturn, result interface{}) error {
+	if result == nil || len(ret.Raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(ret.Raw, result); err != nil {
+		return APIError{
+			HTTPCode:   ret.HTTPCode,
+			SystemCode: CodeResponseCouldNotBeDecoded,
+			Message:    fmt.Sprintf("the response could not be decoded: %s", err.Error()),
+		}
+	}
+	return nil
 }
 
 // makeCall makes the call to the Hubspot API
-func makeCall(httpMethod, endpoint string, data interface{}, requireOAuth bool) (ret *APIReturn, err error) {
+func (c *Client) makeCall(ctx context.Context, httpMethod, endpoint string, data interface{}, requireOAuth bool) (ret *APIReturn, err error) {
 	if strings.HasPrefix(endpoint, "/") {
 		endpoint = endpoint[1:]
 	}
 
-	url := fmt.Sprintf("%s%s", Config.RootURL, endpoint)
-
-	var response *resty.Response
-
-	request := resty.R().
-		SetHeader("Accept", "application/json")
+	queryParams := url.Values{}
+	authToken := ""
 
-	queryParams := map[string]string{}
-
-	if requireOAuth && oAuthToken.AccessToken != "" {
-		request.SetAuthToken(oAuthToken.AccessToken)
+	if requireOAuth {
+		token, tokenErr := c.accessToken(ctx)
+		if tokenErr != nil {
+			c.log("error", "hubspot_sdk_no_oauth", "could not get oauth token for a call that requires oAuth", map[string]interface{}{
+				"err": tokenErr,
+			})
+			return nil, APIError{
+				HTTPCode:   http.StatusUnauthorized,
+				SystemCode: CodeOAuthTokenUnavailable,
+				Message:    tokenErr.Error(),
+				Err:        tokenErr,
+			}
+		}
+		authToken = token
 	} else {
 		// if oauth is required, we do not send up the api key
-		queryParams["hapikey"] = Config.HubspotAPIKey
+		queryParams.Set("hapikey", c.config.HubspotAPIKey)
 	}
-	if Config.HubspotUserID != "" {
-		queryParams["userId"] = Config.HubspotUserID
+	if c.config.HubspotUserID != "" {
+		queryParams.Set("userId", c.config.HubspotUserID)
 	}
 
-	log("info", "api_url", fmt.Sprintf("Calling URL: %s: %s", httpMethod, url), map[string]interface{}{
-		"query": queryParams,
-	})
-
-	// Now, do what we need to do depending on the method
-	var reqErr error
-
-	switch httpMethod {
-	case http.MethodGet:
-		if data != nil {
-			// merge the two data sets
-			dataParsed, dataParsedOK := data.(map[string]string)
-			if !dataParsedOK {
-				return nil, APIError{
-					HTTPCode:   http.StatusBadRequest,
-					SystemCode: "request_error_bad_query_string",
-					Message:    "GET requests must use a map[string]string{} for data",
-				}
+	if httpMethod == http.MethodGet && data != nil {
+		// merge the two data sets; a url.Values can repeat a parameter, which some endpoints use for lists
+		switch dataParsed := data.(type) {
+		case map[string]string:
+			for k, v := range dataParsed {
+				queryParams.Set(k, v)
 			}
+		case url.Values:
 			for k, v := range dataParsed {
-				queryParams[k] = v
+				queryParams[k] = append(queryParams[k], v...)
+			}
+		default:
+			return nil, APIError{
+				HTTPCode:   http.StatusBadRequest,
+				SystemCode: CodeRequestBadQueryString,
+				Message:    "GET requests must use a map[string]string{} or url.Values{} for data",
 			}
 		}
-		response, reqErr = request.SetQueryParams(queryParams).Get(url)
-	case http.MethodDelete:
-		response, reqErr = request.SetQueryParams(queryParams).Delete(url)
-	case http.MethodPost:
-		response, reqErr = request.SetQueryParams(queryParams).SetBody(data).Post(url)
-		fmt.Printf("\n%+v\n", request)
-	case http.MethodPut:
-		response, reqErr = request.SetQueryParams(queryParams).SetBody(data).Put(url)
+	}
+
+	response, reqErr := c.execute(ctx, httpMethod, endpoint, authToken, queryParams, data)
+	if requireOAuth && reqErr == nil && response != nil && response.StatusCode() == http.StatusUnauthorized {
+		// the token may have been revoked or expired early, so refresh it and try exactly once more
+		token, tokenErr := c.refreshRejectedToken(ctx, authToken)
+		if tokenErr == nil {
+			authToken = token
+			response, reqErr = c.execute(ctx, httpMethod, endpoint, authToken, queryParams, data)
+		}
 	}
 
 	if reqErr != nil {
-		log("warning", "unknown_api_error", "we encountered an error calling the API", map[string]interface{}{
-			"err": reqErr,
+		c.log("warning", "unknown_api_error", "we encountered an error calling the API", map[string]interface{}{
+			"method": httpMethod,
+			"path":   "/" + endpoint,
+			"err":    reqErr,
 		})
-		return nil, &APIError{
-			HTTPCode:   http.StatusInternalServerError,
-			SystemCode: "request_error",
-			Message:    reqErr.Error(),
-		}
+		return nil, newRequestError(reqErr)
 	}
 
 	if response == nil {
 		// there is an unknown error from the server
-		log("warning", "unknown_api_error", "we encountered an error calling the API", map[string]interface{}{
-			"response": response,
+		c.log("warning", "unknown_api_error", "we encountered an error calling the API", map[string]interface{}{
+			"method": httpMethod,
+			"path":   "/" + endpoint,
 		})
-		return nil, &APIError{
+		return nil, APIError{
 			HTTPCode:   http.StatusInternalServerError,
-			SystemCode: "request_error",
+			SystemCode: CodeRequestError,
+			Message:    "no response was received",
 		}
-
 	}
 
 	statusCode := response.StatusCode()
 	if statusCode >= http.StatusMultipleChoices {
-		apiError := map[string]interface{}{}
-		message := "error"
-		json.Unmarshal(response.Body(), &apiError)
-		// check to see if there is a message field and we can throw it in the message level
-		if _, ok := apiError["message"]; ok {
-			message = apiError["message"].(string)
-		}
-		return nil, APIError{
-			HTTPCode:   statusCode,
-			SystemCode: "error",
-			Message:    message,
-			Body:       &apiError,
-		}
+		return nil, newResponseError(response)
 	}
 
-	responseData := map[string]interface{}{}
-	json.Unmarshal(response.Body(), &responseData)
-
 	return &APIReturn{
 		HTTPCode: statusCode,
-		Body:     responseData,
+		Raw:      json.RawMessage(response.Body()),
 	}, nil
 }
+
+// execute makes the call, waiting on the rate limiter before each attempt and retrying as the retry policy allows. Every
+// attempt is logged with its method, path, status, latency and attempt number.
+func (c *Client) execute(ctx context.Context, httpMethod, endpoint, authToken string, queryParams url.Values, data interface{}) (response *resty.Response, reqErr error) {
+	fullURL := fmt.Sprintf("%s%s", c.config.RootURL, endpoint)
+	path := "/" + endpoint
+	authMode := AuthModeAPIKey
+	if authToken != "" {
+		authMode = AuthModeOAuth
+	}
+	limiter := c.rateLimiter(authMode)
+
+	// keep trying until we either succeed or the retry policy tells us to give up
+	policy := c.retryPolicy()
+	for attempt := 1; ; attempt++ {
+		if waitErr := limiter.wait(ctx); waitErr != nil {
+			response, reqErr = nil, waitErr
+			break
+		}
+		start := time.Now()
+		response, reqErr = c.send(ctx, httpMethod, fullURL, authToken, queryParams, data)
+		statusCode := 0
+		if response != nil {
+			statusCode = response.StatusCode()
+			limiter.update(response.Header())
+		}
+		callFields := map[string]interface{}{
+			"method":  httpMethod,
+			"path":    path,
+			"status":  statusCode,
+			"latency": time.Since(start),
+			"attempt": attempt,
+		}
+		if reqErr != nil {
+			callFields["err"] = reqErr
+		}
+		if reqErr != nil || statusCode >= http.StatusBadRequest {
+			c.log("warning", "api_call", fmt.Sprintf("Called URL: %s: %s", httpMethod, path), callFields)
+		} else {
+			c.log("info", "api_call", fmt.Sprintf("Called URL: %s: %s", httpMethod, path), callFields)
+		}
+
+		delay, retry := policy.shouldRetry(ctx, httpMethod, attempt, response, reqErr)
+		if !retry {
+			break
+		}
+		c.log("warning", "api_retry", fmt.Sprintf("Retrying URL: %s: %s", httpMethod, path), map[string]interface{}{
+			"method":  httpMethod,
+			"path":    path,
+			"attempt": attempt,
+			"status":  statusCode,
+			"delay":   delay,
+		})
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			response, reqErr = nil, sleepErr
+			break
+		}
+	}
+	return response, reqErr
+}
+
+// send makes a single attempt at a call to the Hubspot API
+func (c *Client) send(ctx context.Context, httpMethod, fullURL, authToken string, queryParams url.Values, data interface{}) (*resty.Response, error) {
+	request := c.http.R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		SetMultiValueQueryParams(queryParams)
+	if authToken != "" {
+		request.SetAuthToken(authToken)
+	}
+
+	switch httpMethod {
+	case http.MethodGet:
+		return request.Get(fullURL)
+	case http.MethodDelete:
+		return request.Delete(fullURL)
+	case http.MethodPost:
+		return request.SetBody(data).Post(fullURL)
+	case http.MethodPut:
+		return request.SetBody(data).Put(fullURL)
+	case http.MethodPatch:
+		return request.SetBody(data).Patch(fullURL)
+	}
+	return nil, nil
+}
@@ -0,0 +1,95 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient builds a Client that talks to a local stand-in server instead of Hubspot. The caller
+// is responsible for closing the server.
+func newTestClient(handler http.HandlerFunc) (*Client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	client := NewClient(&ConfigStruct{
+		Environment:          "dev",
+		RootURL:              server.URL,
+		HubspotAPIKey:        "test-key",
+		HubspotApplicationID: "123",
+	})
+	return client, server
+}
+
+func TestClientsAreIndependent(t *testing.T) {
+	sandboxKeys := []string{}
+	sandbox, sandboxServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		sandboxKeys = append(sandboxKeys, r.URL.Query().Get("hapikey"))
+		json.NewEncoder(w).Encode(map[string]interface{}{"vid": 1})
+	})
+	defer sandboxServer.Close()
+	productionKeys := []string{}
+	production, productionServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		productionKeys = append(productionKeys, r.URL.Query().Get("hapikey"))
+		json.NewEncoder(w).Encode(map[string]interface{}{"vid": 2})
+	})
+	defer productionServer.Close()
+	sandbox.Config().HubspotAPIKey = "sandbox-key"
+	production.Config().HubspotAPIKey = "production-key"
+
+	sandboxContact := Contact{Email: "test@test.com"}
+	err := sandbox.CreateOrUpdateContact(context.Background(), &sandboxContact)
+	require.Nil(t, err)
+	productionContact := Contact{Email: "test@test.com"}
+	err = production.CreateOrUpdateContact(context.Background(), &productionContact)
+	require.Nil(t, err)
+
+	assert.Equal(t, int64(1), sandboxContact.VID)
+	assert.Equal(t, int64(2), productionContact.VID)
+	assert.Equal(t, []string{"sandbox-key"}, sandboxKeys)
+	assert.Equal(t, []string{"production-key"}, productionKeys)
+}
+
+func TestDefaultClientFollowsConfig(t *testing.T) {
+	ConfigSetup()
+	first := DefaultClient()
+	assert.Equal(t, Config, first.Config())
+
+	ConfigSetup()
+	second := DefaultClient()
+	assert.Equal(t, first, second)
+	assert.Equal(t, Config, second.Config())
+}
+
+func TestContextCancelsCall(t *testing.T) {
+	release := make(chan struct{})
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetContactByEmail(ctx, "test@test.com")
+	require.NotNil(t, err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
+
+func TestClientLeavesGlobalLoggerAlone(t *testing.T) {
+	formatter := logrus.StandardLogger().Formatter
+	client := NewClient(nil)
+	client.log("info", "test", "a test message", nil)
+	assert.Equal(t, formatter, logrus.StandardLogger().Formatter)
+	defaultLogger, loggerOK := client.defaultLogger.(*logrusLogger)
+	require.True(t, loggerOK)
+	assert.IsType(t, &logrus.JSONFormatter{}, defaultLogger.logger.(*logrus.Logger).Formatter)
+}
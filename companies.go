@@ -0,0 +1,538 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// companyBatchSize is the most companies Hubspot will accept in a single batch update
+const companyBatchSize = 100
+
+// companyPageSize is the most companies Hubspot will return in one page of a listing
+const companyPageSize = 250
+
+// Company is a business or organization in Hubspot. ID is 0 until the company has been created.
+//
+// The `hubspot` tags map each field to its Hubspot property. Any other properties can be sent with AdditionalProperties,
+// or with SetProperties from your own tagged struct, and ClearProperties blanks out properties in Hubspot. Properties
+// holds every property Hubspot returned when the company was fetched.
+type Company struct {
+	ID                   int64             `hubspot:"-"`
+	Name                 string            `hubspot:"name,omitempty"`
+	Domain               string            `hubspot:"domain,omitempty"`
+	Website              string            `hubspot:"website,omitempty"`
+	Description          string            `hubspot:"description,omitempty"`
+	Industry             string            `hubspot:"industry,omitempty"`
+	Phone                string            `hubspot:"phone,omitempty"`
+	Address              string            `hubspot:"address,omitempty"`
+	City                 string            `hubspot:"city,omitempty"`
+	State                string            `hubspot:"state,omitempty"`
+	Zip                  string            `hubspot:"zip,omitempty"`
+	Country              string            `hubspot:"country,omitempty"`
+	AdditionalProperties *[]ObjectProperty `hubspot:"-"`
+	ClearProperties      []string          `hubspot:"-"`
+	Properties           ObjectProperties  `hubspot:"-"`
+}
+
+// CompanyListOptions controls a company listing. Hubspot only returns the properties that are asked for, so a listing
+// without Properties returns little more than the IDs.
+type CompanyListOptions struct {
+	Properties []string
+	// PropertiesWithHistory also returns the versions of the properties
+	PropertiesWithHistory bool
+	// Count is the number of companies fetched per page, up to 250. It defaults to 250.
+	Count int
+	// Offset resumes a listing from a cursor returned by CompanyIterator.Offset
+	Offset int64
+}
+
+// companyResponse is a company as returned by Hubspot
+type companyResponse struct {
+	CompanyID  int64                             `json:"companyId"`
+	PortalID   int64                             `json:"portalId"`
+	IsDeleted  bool                              `json:"isDeleted"`
+	Properties map[string]objectPropertyResponse `json:"properties"`
+}
+
+// companyListResponse is a single page of companies as returned by Hubspot
+type companyListResponse struct {
+	Companies []companyResponse `json:"companies"`
+	HasMore   bool              `json:"has-more"`
+	Offset    int64             `json:"offset"`
+}
+
+// companyDomainRequest is the body of a lookup by domain
+type companyDomainRequest struct {
+	Limit          int `json:"limit"`
+	RequestOptions struct {
+		Properties []string `json:"properties,omitempty"`
+	} `json:"requestOptions"`
+	Offset companyDomainOffset `json:"offset"`
+}
+
+type companyDomainOffset struct {
+	IsPrimary bool  `json:"isPrimary"`
+	CompanyID int64 `json:"companyId"`
+}
+
+// companyDomainResponse is a single page of companies found by domain
+type companyDomainResponse struct {
+	Results []companyResponse   `json:"results"`
+	HasMore bool                `json:"hasMore"`
+	Offset  companyDomainOffset `json:"offset"`
+}
+
+// companyBatchRequest is a single company in a batch update
+type companyBatchRequest struct {
+	ObjectID   int64            `json:"objectId"`
+	Properties []ObjectProperty `json:"properties"`
+}
+
+// companyContactsResponse is a single page of the VIDs of a company's contacts
+type companyContactsResponse struct {
+	VIDs      []int64 `json:"vids"`
+	HasMore   bool    `json:"hasMore"`
+	VIDOffset int64   `json:"vidOffset"`
+}
+
+// CreateCompany creates a new company. The ID of the company is filled in once it has been created.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/companies/create_company
+func (c *Client) CreateCompany(ctx context.Context, company *Company) error {
+	props, err := company.convertCompanyToProperties()
+	if err != nil {
+		return err
+	}
+	if len(props) == 0 {
+		return APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeCompanyMissingData,
+			Message:    "you must provide at least one property for a company, such as its Name or Domain",
+			Body:       nil,
+		}
+	}
+
+	result := companyResponse{}
+	_, err = c.prepareCall(ctx, EndpointCreateCompany, map[string]string{}, map[string]interface{}{
+		"properties": props,
+	}, &result)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+			apiErr.SystemCode = CodeCompanyCouldNotBeCreated
+			return apiErr
+		}
+		return err
+	}
+	company.populateCompanyFields(result)
+	return nil
+}
+
+// CreateCompany calls Client.CreateCompany on the DefaultClient with a background context
+func CreateCompany(company *Company) error {
+	return DefaultClient().CreateCompany(context.Background(), company)
+}
+
+// UpdateCompany updates an existing company using its ID. Only the properties that are set, and those in
+// ClearProperties, are changed. The company is filled in with what Hubspot returns.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/companies/update_company
+func (c *Client) UpdateCompany(ctx context.Context, company *Company) error {
+	if company.ID == 0 {
+		return companyIDZeroError("updating")
+	}
+	props, err := company.convertCompanyToProperties()
+	if err != nil {
+		return err
+	}
+
+	result := companyResponse{}
+	_, err = c.prepareCall(ctx, EndpointUpdateCompany, map[string]string{
+		":companyID": fmt.Sprintf("%d", company.ID),
+	}, map[string]interface{}{
+		"properties": props,
+	}, &result)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeCompanyNotFound
+				return apiErr
+			}
+			apiErr.SystemCode = CodeCompanyCouldNotBeUpdated
+			return apiErr
+		}
+		return err
+	}
+	company.populateCompanyFields(result)
+	return nil
+}
+
+// UpdateCompany calls Client.UpdateCompany on the DefaultClient with a background context
+func UpdateCompany(company *Company) error {
+	return DefaultClient().UpdateCompany(context.Background(), company)
+}
+
+// BatchUpdateCompanies updates many existing companies at once. Every company needs an ID. The companies are sent in
+// batches of up to 100, which is the most Hubspot allows; if a batch is rejected the error is returned straight away,
+// and the batches before it have already been applied.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/companies/batch-update-companies
+func (c *Client) BatchUpdateCompanies(ctx context.Context, companies []Company) error {
+	if len(companies) == 0 {
+		return APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeCompanyMissingData,
+			Message:    "you must specify at least one company to update",
+			Body:       nil,
+		}
+	}
+	send := make([]companyBatchRequest, len(companies))
+	for i := range companies {
+		if companies[i].ID == 0 {
+			return companyIDZeroError("updating")
+		}
+		props, err := companies[i].convertCompanyToProperties()
+		if err != nil {
+			return err
+		}
+		send[i] = companyBatchRequest{
+			ObjectID:   companies[i].ID,
+			Properties: props,
+		}
+	}
+
+	for start := 0; start < len(send); start += companyBatchSize {
+		end := start + companyBatchSize
+		if end > len(send) {
+			end = len(send)
+		}
+		_, err := c.prepareCall(ctx, EndpointBatchUpdateCompanies, map[string]string{}, send[start:end], nil)
+		if err != nil {
+			if apiErr, apiErrOK := err.(APIError); apiErrOK {
+				apiErr.SystemCode = CodeCompanyCouldNotBeUpdated
+				return apiErr
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchUpdateCompanies calls Client.BatchUpdateCompanies on the DefaultClient with a background context
+func BatchUpdateCompanies(companies []Company) error {
+	return DefaultClient().BatchUpdateCompanies(context.Background(), companies)
+}
+
+// GetCompanyByID gets a single company by its ID, with all of its properties and their history
+//
+// API Doc: https://developers.hubspot.com/docs/methods/companies/get_company
+func (c *Client) GetCompanyByID(ctx context.Context, companyID int64) (Company, error) {
+	company := Company{}
+	if companyID == 0 {
+		return company, companyIDZeroError("getting")
+	}
+	result := companyResponse{}
+	_, err := c.prepareCall(ctx, EndpointGetCompany, map[string]string{
+		":companyID": fmt.Sprintf("%d", companyID),
+	}, nil, &result)
+	if err != nil {
+		return company, companyLookupError(err)
+	}
+	company.populateCompanyFields(result)
+	return company, nil
+}
+
+// GetCompanyByID calls Client.GetCompanyByID on the DefaultClient with a background context
+func GetCompanyByID(companyID int64) (Company, error) {
+	return DefaultClient().GetCompanyByID(context.Background(), companyID)
+}
+
+// GetCompaniesByDomain finds the companies with the domain, such as "wagz.com". Hubspot only returns the properties
+// that are asked for. An empty slice means no company has that domain.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/companies/search_companies_by_domain
+func (c *Client) GetCompaniesByDomain(ctx context.Context, domain string, properties ...string) ([]Company, error) {
+	companies := []Company{}
+	if domain == "" {
+		return companies, APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeCompanyNoDomain,
+			Message:    "you must specify the domain to look up",
+			Body:       nil,
+		}
+	}
+
+	send := companyDomainRequest{
+		Limit: 100,
+	}
+	send.RequestOptions.Properties = properties
+	for {
+		result := companyDomainResponse{}
+		_, err := c.prepareCall(ctx, EndpointGetCompaniesByDomain, map[string]string{
+			":domain": url.PathEscape(domain),
+		}, send, &result)
+		if err != nil {
+			return companies, companyLookupError(err)
+		}
+		for i := range result.Results {
+			company := Company{}
+			company.populateCompanyFields(result.Results[i])
+			companies = append(companies, company)
+		}
+		if !result.HasMore || result.Offset == send.Offset {
+			return companies, nil
+		}
+		send.Offset = result.Offset
+	}
+}
+
+// GetCompaniesByDomain calls Client.GetCompaniesByDomain on the DefaultClient with a background context
+func GetCompaniesByDomain(domain string, properties ...string) ([]Company, error) {
+	return DefaultClient().GetCompaniesByDomain(context.Background(), domain, properties...)
+}
+
+// DeleteCompanyByID deletes a single company by its ID
+//
+// API Doc: https://developers.hubspot.com/docs/methods/companies/delete_company
+func (c *Client) DeleteCompanyByID(ctx context.Context, companyID int64) error {
+	if companyID == 0 {
+		return companyIDZeroError("deleting")
+	}
+	_, err := c.prepareCall(ctx, EndpointDeleteCompany, map[string]string{
+		":companyID": fmt.Sprintf("%d", companyID),
+	}, nil, nil)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeCompanyNotFound
+				return apiErr
+			}
+			apiErr.SystemCode = CodeCompanyCouldNotBeDeleted
+			return apiErr
+		}
+	}
+	return err
+}
+
+// DeleteCompanyByID calls Client.DeleteCompanyByID on the DefaultClient with a background context
+func DeleteCompanyByID(companyID int64) error {
+	return DefaultClient().DeleteCompanyByID(context.Background(), companyID)
+}
+
+// CompanyIterator walks through a listing of companies, fetching pages from Hubspot as they are needed. It is used the
+// same way as a ContactIterator.
+type CompanyIterator struct {
+	pager
+	page []Company
+}
+
+// Next advances to the next company. It returns false when there are no more companies or an error occurred, which is
+// then available from Err.
+func (it *CompanyIterator) Next() bool {
+	return it.next()
+}
+
+// Company returns the current company. It is only valid after a call to Next returns true.
+func (it *CompanyIterator) Company() Company {
+	if i := it.current(); i >= 0 {
+		return it.page[i]
+	}
+	return Company{}
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *CompanyIterator) Err() error {
+	return it.err
+}
+
+// Offset returns a cursor to resume the listing from with CompanyListOptions.Offset. See pager.cursor for where it
+// points.
+func (it *CompanyIterator) Offset() int64 {
+	return it.offset()
+}
+
+// ListCompanies walks every company in the portal
+//
+// API Doc: https://developers.hubspot.com/docs/methods/companies/get-all-companies
+func (c *Client) ListCompanies(ctx context.Context, opts *CompanyListOptions) *CompanyIterator {
+	if opts == nil {
+		opts = &CompanyListOptions{}
+	}
+	count := pageCount(opts.Count, companyPageSize)
+	propertyParam := "properties"
+	if opts.PropertiesWithHistory {
+		propertyParam = "propertiesWithHistory"
+	}
+
+	it := &CompanyIterator{}
+	it.pager = newPager(ctx, opts.Offset, func(ctx context.Context, offset int64) (int, int64, bool, error) {
+		query := url.Values{}
+		query.Set("limit", fmt.Sprintf("%d", count))
+		if offset != 0 {
+			query.Set("offset", fmt.Sprintf("%d", offset))
+		}
+		for _, property := range opts.Properties {
+			query.Add(propertyParam, property)
+		}
+
+		result := companyListResponse{}
+		_, err := c.prepareCall(ctx, EndpointListCompanies, map[string]string{}, query, &result)
+		if err != nil {
+			if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+				apiErr.SystemCode = CodeCompanyCouldNotBeListed
+				return 0, offset, false, apiErr
+			}
+			return 0, offset, false, err
+		}
+		it.page = make([]Company, len(result.Companies))
+		for i := range result.Companies {
+			it.page[i].populateCompanyFields(result.Companies[i])
+		}
+		return len(it.page), result.Offset, result.HasMore, nil
+	})
+	return it
+}
+
+// ListCompanies calls Client.ListCompanies on the DefaultClient with a background context
+func ListCompanies(opts *CompanyListOptions) *CompanyIterator {
+	return DefaultClient().ListCompanies(context.Background(), opts)
+}
+
+// AddContactToCompany associates the contact with the company. A contact can only be associated with one company, so
+// this replaces any company the contact already had.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/companies/add_contact_to_company
+func (c *Client) AddContactToCompany(ctx context.Context, companyID, vid int64) error {
+	return c.changeCompanyContact(ctx, EndpointAddContactToCompany, companyID, vid, CodeCompanyContactCouldNotBeAdded)
+}
+
+// AddContactToCompany calls Client.AddContactToCompany on the DefaultClient with a background context
+func AddContactToCompany(companyID, vid int64) error {
+	return DefaultClient().AddContactToCompany(context.Background(), companyID, vid)
+}
+
+// RemoveContactFromCompany removes the association between the contact and the company
+//
+// API Doc: https://developers.hubspot.com/docs/methods/companies/remove_contact_from_company
+func (c *Client) RemoveContactFromCompany(ctx context.Context, companyID, vid int64) error {
+	return c.changeCompanyContact(ctx, EndpointRemoveContactFromCompany, companyID, vid, CodeCompanyContactCouldNotBeRemoved)
+}
+
+// RemoveContactFromCompany calls Client.RemoveContactFromCompany on the DefaultClient with a background context
+func RemoveContactFromCompany(companyID, vid int64) error {
+	return DefaultClient().RemoveContactFromCompany(context.Background(), companyID, vid)
+}
+
+// GetCompanyContactVIDs gets the VIDs of all of the contacts associated with the company. Use GetContactsByVIDs to
+// fetch the contacts themselves.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/companies/get_company_contacts_by_id
+func (c *Client) GetCompanyContactVIDs(ctx context.Context, companyID int64) ([]int64, error) {
+	vids := []int64{}
+	if companyID == 0 {
+		return vids, companyIDZeroError("getting the contacts of")
+	}
+	var vidOffset int64
+	for {
+		query := url.Values{}
+		query.Set("count", "100")
+		if vidOffset != 0 {
+			query.Set("vidOffset", fmt.Sprintf("%d", vidOffset))
+		}
+		result := companyContactsResponse{}
+		_, err := c.prepareCall(ctx, EndpointGetCompanyContactVIDs, map[string]string{
+			":companyID": fmt.Sprintf("%d", companyID),
+		}, query, &result)
+		if err != nil {
+			return vids, companyLookupError(err)
+		}
+		vids = append(vids, result.VIDs...)
+		if !result.HasMore || result.VIDOffset == vidOffset {
+			return vids, nil
+		}
+		vidOffset = result.VIDOffset
+	}
+}
+
+// GetCompanyContactVIDs calls Client.GetCompanyContactVIDs on the DefaultClient with a background context
+func GetCompanyContactVIDs(companyID int64) ([]int64, error) {
+	return DefaultClient().GetCompanyContactVIDs(context.Background(), companyID)
+}
+
+// changeCompanyContact adds or removes a contact from a company
+func (c *Client) changeCompanyContact(ctx context.Context, endpoint string, companyID, vid int64, failureCode string) error {
+	if companyID == 0 {
+		return companyIDZeroError("adding or removing contacts on")
+	}
+	if vid == 0 {
+		return APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeContactVIDZero,
+			Message:    "the VID for the contact cannot be 0",
+			Body:       nil,
+		}
+	}
+	_, err := c.prepareCall(ctx, endpoint, map[string]string{
+		":companyID": fmt.Sprintf("%d", companyID),
+		":vid":       fmt.Sprintf("%d", vid),
+	}, nil, nil)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeCompanyNotFound
+				return apiErr
+			}
+			apiErr.SystemCode = failureCode
+			return apiErr
+		}
+	}
+	return err
+}
+
+// SetProperties adds the properties of your own struct to the company's AdditionalProperties, using its `hubspot` struct
+// tags as described in MarshalProperties
+func (company *Company) SetProperties(v interface{}) error {
+	return appendObjectProperties(&company.AdditionalProperties, v)
+}
+
+// ClearProperty adds the properties to ClearProperties, so they are blanked out the next time the company is saved
+func (company *Company) ClearProperty(names ...string) {
+	company.ClearProperties = append(company.ClearProperties, names...)
+}
+
+// populateCompanyFields fills in the company from Hubspot's representation of it
+func (company *Company) populateCompanyFields(result companyResponse) {
+	company.ID = result.CompanyID
+	company.Properties = newObjectProperties(result.Properties)
+	// the company only has string fields, which can't fail to convert
+	UnmarshalProperties(company.Properties.values(), company)
+}
+
+// convertCompanyToProperties builds the properties to send for the company
+func (company *Company) convertCompanyToProperties() ([]ObjectProperty, error) {
+	return objectPropertyList(company, company.AdditionalProperties, company.ClearProperties)
+}
+
+// companyLookupError sets the system code on an error from fetching companies
+func companyLookupError(err error) error {
+	if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+		if apiErr.HTTPCode == 404 {
+			apiErr.SystemCode = CodeCompanyNotFound
+			return apiErr
+		}
+		apiErr.SystemCode = CodeGeneralError
+		return apiErr
+	}
+	return err
+}
+
+// companyIDZeroError is returned when a company is needed but its ID is missing
+func companyIDZeroError(action string) APIError {
+	return APIError{
+		HTTPCode:   http.StatusBadRequest,
+		SystemCode: CodeCompanyIDZero,
+		Message:    fmt.Sprintf("the ID for the company cannot be 0 when %s it", action),
+		Body:       nil,
+	}
+}
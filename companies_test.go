@@ -0,0 +1,202 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCompanyResponse = `{
+	"portalId": 62515,
+	"companyId": 10444744,
+	"isDeleted": false,
+	"properties": {
+		"name": {"value": "Wagz", "timestamp": 1577836800000, "source": "API", "sourceId": null, "versions": [
+			{"name": "name", "value": "Wagz", "timestamp": 1577836800000, "source": "API", "sourceVid": []}
+		]},
+		"domain": {"value": "wagz.com", "timestamp": 1577836800000, "source": "CRM_UI", "sourceId": "test@wagz.com"},
+		"numberofemployees": {"value": "25", "timestamp": 1577836800000, "source": "API"}
+	}
+}`
+
+func TestCompanyWrites(t *testing.T) {
+	status := http.StatusOK
+	var method, path string
+	var sent interface{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, testCompanyResponse)
+	})
+	defer server.Close()
+
+	err := client.CreateCompany(context.Background(), &Company{})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeCompanyMissingData, err.(APIError).SystemCode)
+	err = client.UpdateCompany(context.Background(), &Company{Name: "Wagz"})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeCompanyIDZero, err.(APIError).SystemCode)
+	assert.Equal(t, "", path)
+
+	company := Company{Name: "Wagz", Domain: "wagz.com"}
+	require.Nil(t, company.SetProperties(struct {
+		Employees int `hubspot:"numberofemployees"`
+	}{Employees: 25}))
+	err = client.CreateCompany(context.Background(), &company)
+	require.Nil(t, err)
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/companies/v2/companies", path)
+	assert.Equal(t, map[string]interface{}{"properties": []interface{}{
+		map[string]interface{}{"name": "name", "value": "Wagz"},
+		map[string]interface{}{"name": "domain", "value": "wagz.com"},
+		map[string]interface{}{"name": "numberofemployees", "value": "25"},
+	}}, sent)
+	assert.Equal(t, int64(10444744), company.ID)
+	employees, err := company.Properties.Int("numberofemployees")
+	require.Nil(t, err)
+	assert.Equal(t, int64(25), employees)
+	assert.Equal(t, "CRM_UI", company.Properties["domain"].Source)
+	assert.Equal(t, "test@wagz.com", company.Properties["domain"].SourceID)
+	require.Len(t, company.Properties.Versions("name"), 1)
+
+	update := Company{ID: 10444744, Description: "Smart pet products"}
+	update.ClearProperty("phone")
+	err = client.UpdateCompany(context.Background(), &update)
+	require.Nil(t, err)
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/companies/v2/companies/10444744", path)
+	assert.Equal(t, map[string]interface{}{"properties": []interface{}{
+		map[string]interface{}{"name": "description", "value": "Smart pet products"},
+		map[string]interface{}{"name": "phone", "value": ""},
+	}}, sent)
+
+	companies := []Company{}
+	for i := int64(1); i <= 150; i++ {
+		companies = append(companies, Company{ID: i, Industry: "Pets"})
+	}
+	status = http.StatusAccepted
+	err = client.BatchUpdateCompanies(context.Background(), companies)
+	require.Nil(t, err)
+	assert.Equal(t, "/companies/v1/batch-async/update", path)
+	// the last batch has what was left over
+	require.Len(t, sent, 50)
+	assert.Equal(t, float64(101), sent.([]interface{})[0].(map[string]interface{})["objectId"])
+	err = client.BatchUpdateCompanies(context.Background(), []Company{{Name: "No ID"}})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeCompanyIDZero, err.(APIError).SystemCode)
+
+	status = http.StatusNotFound
+	err = client.UpdateCompany(context.Background(), &update)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeCompanyNotFound, err.(APIError).SystemCode)
+	err = client.DeleteCompanyByID(context.Background(), 10444744)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeCompanyNotFound, err.(APIError).SystemCode)
+	assert.Equal(t, http.MethodDelete, method)
+
+	status = http.StatusBadRequest
+	err = client.DeleteCompanyByID(context.Background(), 10444744)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeCompanyCouldNotBeDeleted, err.(APIError).SystemCode)
+}
+
+func TestCompanyLookups(t *testing.T) {
+	var sent companyDomainRequest
+	var escapedPath string
+	queries := []url.Values{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		escapedPath = r.URL.EscapedPath()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/companies/v2/companies/10444744":
+			fmt.Fprint(w, testCompanyResponse)
+		case "/companies/v2/domains/wagz.com/companies":
+			sent = companyDomainRequest{}
+			json.NewDecoder(r.Body).Decode(&sent)
+			if sent.Offset.CompanyID == 0 {
+				fmt.Fprintf(w, `{"results": [%s], "hasMore": true, "offset": {"companyId": 10444744, "isPrimary": true}}`, testCompanyResponse)
+				return
+			}
+			fmt.Fprint(w, `{"results": [{"companyId": 2, "properties": {"name": {"value": "Wagz Labs"}}}], "hasMore": false, "offset": {"companyId": 2, "isPrimary": false}}`)
+		case "/companies/v2/companies/paged":
+			if r.URL.Query().Get("offset") == "" {
+				fmt.Fprintf(w, `{"companies": [%s, {"companyId": 2}], "has-more": true, "offset": 2}`, testCompanyResponse)
+				return
+			}
+			fmt.Fprint(w, `{"companies": [{"companyId": 3}], "has-more": false, "offset": 3}`)
+		case "/companies/v2/companies/10444744/vids":
+			if r.URL.Query().Get("vidOffset") == "" {
+				fmt.Fprint(w, `{"vids": [1, 2], "hasMore": true, "vidOffset": 2}`)
+				return
+			}
+			fmt.Fprint(w, `{"vids": [3], "hasMore": false, "vidOffset": 3}`)
+		case "/companies/v2/companies/10444744/contacts/42":
+			if r.Method == http.MethodPut {
+				fmt.Fprint(w, testCompanyResponse)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	company, err := client.GetCompanyByID(context.Background(), 10444744)
+	require.Nil(t, err)
+	assert.Equal(t, "Wagz", company.Name)
+	assert.Equal(t, "wagz.com", company.Domain)
+	_, err = client.GetCompanyByID(context.Background(), 1)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeCompanyNotFound, err.(APIError).SystemCode)
+
+	companies, err := client.GetCompaniesByDomain(context.Background(), "wagz.com", "name", "domain")
+	require.Nil(t, err)
+	require.Len(t, companies, 2)
+	assert.Equal(t, "Wagz Labs", companies[1].Name)
+	assert.Equal(t, []string{"name", "domain"}, sent.RequestOptions.Properties)
+	assert.Equal(t, int64(10444744), sent.Offset.CompanyID)
+	_, err = client.GetCompaniesByDomain(context.Background(), "")
+	require.NotNil(t, err)
+	assert.Equal(t, CodeCompanyNoDomain, err.(APIError).SystemCode)
+	// the domain is escaped, so it can't change the path
+	_, err = client.GetCompaniesByDomain(context.Background(), "wagz.com/x?y")
+	require.NotNil(t, err)
+	assert.Equal(t, "/companies/v2/domains/wagz.com%2Fx%3Fy/companies", escapedPath)
+
+	queries = []url.Values{}
+	it := client.ListCompanies(context.Background(), &CompanyListOptions{Properties: []string{"name", "domain"}, PropertiesWithHistory: true})
+	ids := []int64{}
+	for it.Next() {
+		ids = append(ids, it.Company().ID)
+	}
+	require.Nil(t, it.Err())
+	assert.Equal(t, []int64{10444744, 2, 3}, ids)
+	assert.Equal(t, int64(3), it.Offset())
+	assert.Equal(t, "250", queries[0].Get("limit"))
+	assert.Equal(t, []string{"name", "domain"}, queries[0]["propertiesWithHistory"])
+	assert.Equal(t, "2", queries[1].Get("offset"))
+
+	vids, err := client.GetCompanyContactVIDs(context.Background(), 10444744)
+	require.Nil(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, vids)
+
+	require.Nil(t, client.AddContactToCompany(context.Background(), 10444744, 42))
+	require.Nil(t, client.RemoveContactFromCompany(context.Background(), 10444744, 42))
+	err = client.AddContactToCompany(context.Background(), 10444744, 0)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeContactVIDZero, err.(APIError).SystemCode)
+	err = client.AddContactToCompany(context.Background(), 1, 42)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeCompanyNotFound, err.(APIError).SystemCode)
+}
@@ -4,14 +4,16 @@ import (
 	"fmt"
 	"os"
 	"strings"
-
-	"github.com/sirupsen/logrus"
 )
 
-// Config is the global configuration object that holds global configuration settings
+// defaultOAuthAuthorizeURL is where HubSpot users are sent to install an app
+const defaultOAuthAuthorizeURL = "https://app.hubspot.com/oauth/authorize"
+
+// Config is the global configuration object that holds global configuration settings. It is nil until ConfigSetup is
+// called or the DefaultClient is first used, at which point it is read from the environment.
 var Config *ConfigStruct
 
-//ConfigStruct holds the various configuration options
+// ConfigStruct holds the various configuration options
 type ConfigStruct struct {
 	Environment   string
 	RootURL       string
@@ -23,10 +25,32 @@ type ConfigStruct struct {
 	HubspotClientID          string
 	HubspotClientSecret      string
 	HubSpotOAuthRefreshToken string
+	// HubspotPortalID identifies the portal the oAuth token belongs to in the TokenStore
+	HubspotPortalID string
+	// TokenStore persists oAuth tokens; if nil, tokens are only kept in memory by the client
+	TokenStore TokenStore
+	// HubspotOAuthRedirectURI and HubspotOAuthAuthorizeURL are used when installing an app with the authorization code flow
+	HubspotOAuthRedirectURI  string
+	HubspotOAuthAuthorizeURL string
+	// Retry controls how failed calls are retried; if nil, DefaultRetryPolicy is used
+	Retry *RetryPolicy
+	// APIKeyRateLimit and OAuthRateLimit limit how quickly calls are made with each kind of authentication; if nil,
+	// DefaultRateLimit is used
+	APIKeyRateLimit *RateLimit
+	OAuthRateLimit  *RateLimit
+	// Logger receives the client's log entries when Logging is on; if nil, a logrus logger with a JSON formatter is used
+	Logger Logger
 }
 
-// ConfigSetup sets up the config struct with data from the environment
+// ConfigSetup sets up the global config struct with data from the environment
 func ConfigSetup() *ConfigStruct {
+	c := configFromEnvironment()
+	Config = c
+	return c
+}
+
+// configFromEnvironment builds a new config struct from the environment without touching the global Config
+func configFromEnvironment() *ConfigStruct {
 	c := new(ConfigStruct)
 
 	c.Environment = strings.ToLower(os.Getenv("HUBSPOT_SDK_ENV"))
@@ -61,6 +85,12 @@ func ConfigSetup() *ConfigStruct {
 	c.HubspotClientID = os.Getenv("HUBSPOT_SDK_CLIENT_ID")
 	c.HubspotClientSecret = os.Getenv("HUBSPOT_SDK_CLIENT_SECRET")
 	c.HubSpotOAuthRefreshToken = os.Getenv("HUBSPOT_SDK_OAUTH_REFRESH_TOKEN")
+	c.HubspotPortalID = os.Getenv("HUBSPOT_SDK_PORTAL_ID")
+	c.HubspotOAuthRedirectURI = os.Getenv("HUBSPOT_SDK_OAUTH_REDIRECT_URI")
+	c.HubspotOAuthAuthorizeURL = os.Getenv("HUBSPOT_SDK_OAUTH_AUTHORIZE_URL")
+	if c.HubspotOAuthAuthorizeURL == "" {
+		c.HubspotOAuthAuthorizeURL = defaultOAuthAuthorizeURL
+	}
 
 	shouldLog := strings.ToLower(os.Getenv("HUBSPOT_SDK_LOGGING"))
 	if shouldLog == "off" || shouldLog == "false" || shouldLog == "no" {
@@ -69,35 +99,39 @@ func ConfigSetup() *ConfigStruct {
 		c.Logging = true
 	}
 
-	Config = c
-
 	return c
 }
 
-// init is called when the host application starts up and sets the
-// Configuration and logging settings
-func init() {
-	ConfigSetup()
-	logrus.SetFormatter(&logrus.JSONFormatter{})
-}
-
-// log provides structured logging through logrus. We support info, warning, and error
-func log(level, key, message string, data interface{}) string {
-	if Config.Logging {
+// log provides structured logging through the configured Logger. We support info, warning, and error. If data is a
+// map, its entries become fields of the entry; anything else is logged in a data field. Secrets are always redacted.
+func (c *Client) log(level, key, message string, data interface{}) string {
+	if c.config.Logging {
 		level = strings.ToLower(level)
 
-		fields := logrus.Fields{
-			"key":  key,
-			"data": data,
+		fields := map[string]interface{}{}
+		if dataFields, dataFieldsOK := data.(map[string]interface{}); dataFieldsOK {
+			for k, v := range dataFields {
+				fields[k] = v
+			}
+		} else if data != nil {
+			fields["data"] = data
+		}
+		redactor := c.redactor()
+		fields = redactor.redactFields(fields)
+		fields["key"] = key
+		message = redactor.redactString(message)
+
+		logger := c.config.Logger
+		if logger == nil {
+			logger = c.defaultLogger
 		}
-
 		switch level {
 		case "info":
-			logrus.WithFields(fields).Info(message)
+			logger.Info(message, fields)
 		case "warning":
-			logrus.WithFields(fields).Warning(message)
+			logger.Warning(message, fields)
 		case "error":
-			logrus.WithFields(fields).Error(message)
+			logger.Error(message, fields)
 		}
 		return fmt.Sprintf("%s: %s", strings.ToUpper(level), key)
 	}
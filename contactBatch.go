@@ -0,0 +1,234 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// contactBatchUpdateSize is the most contacts Hubspot will accept in a single batch create or update
+const contactBatchUpdateSize = 1000
+
+// ContactBatchResult is the outcome for a single contact sent to BatchCreateOrUpdateContacts
+type ContactBatchResult struct {
+	// Index is the position of the contact in the slice that was passed in
+	Index int
+	Email string
+	VID   int64
+	// Err is nil if Hubspot accepted the contact. Otherwise it is an APIError describing why it was rejected.
+	Err error
+}
+
+// contactBatchRequest is a single contact in a batch create or update. Contacts with a VID are matched on it, otherwise
+// on their email.
+type contactBatchRequest struct {
+	VID        int64               `json:"vid,omitempty"`
+	Email      string              `json:"email,omitempty"`
+	Properties []map[string]string `json:"properties"`
+}
+
+// contactBatchErrorResponse holds the per contact details Hubspot sends when it rejects a batch
+type contactBatchErrorResponse struct {
+	InvalidEmails   []string `json:"invalidEmails"`
+	FailureMessages []struct {
+		Index int `json:"index"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		PropertyValidationResult *ValidationResult `json:"propertyValidationResult"`
+	} `json:"failureMessages"`
+}
+
+// BatchCreateOrUpdateContacts creates or updates many contacts at once. Contacts with a VID are updated by VID and the
+// rest are created or updated by email. The contacts are sent in batches of up to 1000, which is the most Hubspot allows.
+//
+// Hubspot rejects a whole batch if any contact in it is invalid. When it says which contacts were the problem, those are
+// reported and the rest of the batch is sent again, so one bad email doesn't stop everyone else from being saved. The
+// results have one entry per contact, in the same order as the input. If any contact failed, the error is an APIError
+// with CodeContactBatchHadFailures that wraps the first failure.
+//
+// Hubspot processes the batch asynchronously, so the VIDs of newly created contacts are not returned.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/contacts/batch_create_or_update
+func (c *Client) BatchCreateOrUpdateContacts(ctx context.Context, contacts []Contact) ([]ContactBatchResult, error) {
+	results := make([]ContactBatchResult, len(contacts))
+	if len(contacts) == 0 {
+		return results, contactBatchMissingDataError("contacts")
+	}
+
+	pending := []int{}
+	for i := range contacts {
+		results[i] = ContactBatchResult{
+			Index: i,
+			Email: contacts[i].Email,
+			VID:   contacts[i].VID,
+		}
+		if contacts[i].Email == "" && contacts[i].VID == 0 {
+			results[i].Err = APIError{
+				HTTPCode:   http.StatusBadRequest,
+				SystemCode: CodeContactNoEmail,
+				Message:    "you must provide an Email or VID for each contact",
+				Body:       nil,
+			}
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	for start := 0; start < len(pending); start += contactBatchUpdateSize {
+		end := start + contactBatchUpdateSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		c.sendContactBatch(ctx, contacts, pending[start:end], results, true)
+	}
+
+	failed := 0
+	var firstErr error
+	for i := range results {
+		if results[i].Err != nil {
+			if firstErr == nil {
+				firstErr = results[i].Err
+			}
+			failed++
+		}
+	}
+	if firstErr == nil {
+		return results, nil
+	}
+	apiErr := APIError{
+		HTTPCode:   http.StatusBadRequest,
+		SystemCode: CodeContactBatchHadFailures,
+		Message:    fmt.Sprintf("%d of %d contacts could not be created or updated: %s", failed, len(contacts), firstErr.Error()),
+		Err:        firstErr,
+	}
+	if firstAPIErr, firstAPIErrOK := firstErr.(APIError); firstAPIErrOK {
+		apiErr.HTTPCode = firstAPIErr.HTTPCode
+	}
+	return results, apiErr
+}
+
+// BatchCreateOrUpdateContacts calls Client.BatchCreateOrUpdateContacts on the DefaultClient with a background context
+func BatchCreateOrUpdateContacts(contacts []Contact) ([]ContactBatchResult, error) {
+	return DefaultClient().BatchCreateOrUpdateContacts(context.Background(), contacts)
+}
+
+// sendContactBatch sends the contacts at the indexes as a single batch and records the outcome in the results. If
+// Hubspot names the contacts it rejected and resend is true, the others are sent once more on their own.
+func (c *Client) sendContactBatch(ctx context.Context, contacts []Contact, indexes []int, results []ContactBatchResult, resend bool) {
+	send := make([]contactBatchRequest, len(indexes))
+	for i, index := range indexes {
+		send[i] = contactBatchRequest{
+			Properties: contacts[index].convertContactToProperties(),
+		}
+		if contacts[index].VID != 0 {
+			send[i].VID = contacts[index].VID
+		} else {
+			send[i].Email = contacts[index].Email
+		}
+	}
+
+	_, err := c.prepareCall(ctx, EndpointBatchCreateOrUpdateContacts, map[string]string{}, send, nil)
+	if err == nil {
+		return
+	}
+
+	apiErr, apiErrOK := err.(APIError)
+	if !apiErrOK {
+		for _, index := range indexes {
+			results[index].Err = err
+		}
+		return
+	}
+	apiErr.SystemCode = CodeContactCouldNotBeCreated
+
+	rejected := contactBatchRejections(apiErr, contacts, indexes)
+	if len(rejected) == 0 {
+		// nothing points at a particular contact, so the whole batch failed
+		for _, index := range indexes {
+			results[index].Err = apiErr
+		}
+		return
+	}
+
+	retry := []int{}
+	for _, index := range indexes {
+		if contactErr, found := rejected[index]; found {
+			results[index].Err = contactErr
+			continue
+		}
+		retry = append(retry, index)
+	}
+	if len(retry) == 0 {
+		return
+	}
+	if !resend {
+		for _, index := range retry {
+			results[index].Err = apiErr
+		}
+		return
+	}
+	c.sendContactBatch(ctx, contacts, retry, results, false)
+}
+
+// contactBatchRejections works out which contacts in a rejected batch caused the rejection, keyed by their index in the
+// input
+func contactBatchRejections(apiErr APIError, contacts []Contact, indexes []int) map[int]APIError {
+	rejected := map[int]APIError{}
+	if apiErr.Body == nil {
+		return rejected
+	}
+	raw, err := json.Marshal(apiErr.Body)
+	if err != nil {
+		return rejected
+	}
+	parsed := contactBatchErrorResponse{}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return rejected
+	}
+
+	for _, email := range parsed.InvalidEmails {
+		for _, index := range indexes {
+			if strings.EqualFold(contacts[index].Email, email) {
+				rejected[index] = APIError{
+					HTTPCode:      apiErr.HTTPCode,
+					SystemCode:    CodeContactInvalidEmail,
+					Message:       fmt.Sprintf("%s is not a valid email address", contacts[index].Email),
+					CorrelationID: apiErr.CorrelationID,
+					RequestID:     apiErr.RequestID,
+				}
+			}
+		}
+	}
+
+	for _, failure := range parsed.FailureMessages {
+		// the index Hubspot returns is the position in the batch we sent
+		if failure.Index < 0 || failure.Index >= len(indexes) {
+			continue
+		}
+		index := indexes[failure.Index]
+		if _, found := rejected[index]; found {
+			continue
+		}
+		contactErr := APIError{
+			HTTPCode:      apiErr.HTTPCode,
+			SystemCode:    CodeContactCouldNotBeCreated,
+			Message:       apiErr.Message,
+			CorrelationID: apiErr.CorrelationID,
+			RequestID:     apiErr.RequestID,
+		}
+		if failure.Error != nil && failure.Error.Message != "" {
+			contactErr.Message = failure.Error.Message
+		}
+		if failure.PropertyValidationResult != nil {
+			contactErr.ValidationResults = []ValidationResult{*failure.PropertyValidationResult}
+			if failure.PropertyValidationResult.Message != "" {
+				contactErr.Message = failure.PropertyValidationResult.Message
+			}
+		}
+		rejected[index] = contactErr
+	}
+	return rejected
+}
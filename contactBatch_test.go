@@ -0,0 +1,102 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactBatchCreateOrUpdate(t *testing.T) {
+	batches := [][]contactBatchRequest{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/contacts/v1/contact/batch/", r.URL.Path)
+		batch := []contactBatchRequest{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&batch))
+		batches = append(batches, batch)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	defer server.Close()
+
+	_, err := client.BatchCreateOrUpdateContacts(context.Background(), []Contact{})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeContactBatchMissingData, err.(APIError).SystemCode)
+
+	contacts := []Contact{}
+	for i := 0; i < 2500; i++ {
+		contacts = append(contacts, Contact{Email: fmt.Sprintf("test-%d@test.com", i), FirstName: "Test"})
+	}
+	contacts[5] = Contact{VID: 42, FirstName: "Updated"}
+	results, err := client.BatchCreateOrUpdateContacts(context.Background(), contacts)
+	require.Nil(t, err)
+	require.Len(t, results, 2500)
+	require.Len(t, batches, 3)
+	assert.Len(t, batches[0], 1000)
+	assert.Len(t, batches[1], 1000)
+	assert.Len(t, batches[2], 500)
+	assert.Equal(t, "test-0@test.com", batches[0][0].Email)
+	assert.Contains(t, batches[0][0].Properties, map[string]string{"property": "firstname", "value": "Test"})
+	assert.Equal(t, int64(42), batches[0][5].VID)
+	assert.Equal(t, "", batches[0][5].Email)
+	assert.Equal(t, 2499, results[2499].Index)
+	assert.Nil(t, results[2499].Err)
+}
+
+func TestContactBatchReportsFailures(t *testing.T) {
+	batches := [][]contactBatchRequest{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		batch := []contactBatchRequest{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&batch))
+		batches = append(batches, batch)
+		if len(batches) > 1 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{
+			"status": "error",
+			"message": "Errors found processing batch update",
+			"correlationId": "abc",
+			"invalidEmails": ["bad-email"],
+			"failureMessages": [
+				{"index": 1, "error": {"status": "error", "message": "Email address bad-email is invalid"}},
+				{"index": 2, "propertyValidationResult": {"isValid": false, "message": "not a valid option", "error": "INVALID_OPTION", "name": "lifecyclestage"}}
+			]
+		}`)
+	})
+	defer server.Close()
+
+	results, err := client.BatchCreateOrUpdateContacts(context.Background(), []Contact{
+		{Email: "good@test.com"},
+		{Email: "bad-email"},
+		{Email: "bad-property@test.com", AdditionalProperties: &[]ContactProperty{{Property: "lifecyclestage", Value: "nope"}}},
+		{FirstName: "No email"},
+		{Email: "also-good@test.com"},
+	})
+	require.NotNil(t, err)
+	apiErr, cOK := err.(APIError)
+	require.True(t, cOK)
+	assert.Equal(t, CodeContactBatchHadFailures, apiErr.SystemCode)
+	assert.Contains(t, apiErr.Message, "3 of 5")
+
+	// the contacts that were fine were sent again on their own
+	require.Len(t, batches, 2)
+	require.Len(t, batches[1], 2)
+	assert.Equal(t, "good@test.com", batches[1][0].Email)
+	assert.Equal(t, "also-good@test.com", batches[1][1].Email)
+
+	assert.Nil(t, results[0].Err)
+	assert.Equal(t, CodeContactInvalidEmail, results[1].Err.(APIError).SystemCode)
+	propertyErr := results[2].Err.(APIError)
+	assert.Equal(t, CodeContactCouldNotBeCreated, propertyErr.SystemCode)
+	assert.Equal(t, "not a valid option", propertyErr.Message)
+	assert.Equal(t, "lifecyclestage", propertyErr.ValidationResults[0].Name)
+	assert.Equal(t, "abc", propertyErr.CorrelationID)
+	assert.Equal(t, CodeContactNoEmail, results[3].Err.(APIError).SystemCode)
+	assert.Nil(t, results[4].Err)
+}
@@ -0,0 +1,167 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+)
+
+// contactListPageSize is the most contacts Hubspot will return in one page of a listing
+const contactListPageSize = 100
+
+// ContactOffset is the cursor Hubspot uses to page through contacts. It is safe to save, for example as JSON, and pass
+// back in ContactListOptions or ContactSearchOptions to resume a listing or search later.
+type ContactOffset struct {
+	VIDOffset  int64 `json:"vidOffset,omitempty"`
+	TimeOffset int64 `json:"timeOffset,omitempty"`
+	// Offset is the position in the results of a search
+	Offset int64 `json:"offset,omitempty"`
+}
+
+// ContactListOptions controls a contact listing. A nil options starts from the beginning and uses Hubspot's defaults for
+// the contact fields returned.
+type ContactListOptions struct {
+	ContactQueryOptions
+	// Count is the number of contacts fetched per page, up to 100. It defaults to 100.
+	Count int
+	// Offset resumes a listing from a cursor returned by ContactIterator.Offset
+	Offset ContactOffset
+}
+
+// contactListResponse is a single page of contacts as returned by Hubspot
+type contactListResponse struct {
+	Contacts   []contactResponse `json:"contacts"`
+	HasMore    bool              `json:"has-more"`
+	VIDOffset  int64             `json:"vid-offset"`
+	TimeOffset int64             `json:"time-offset"`
+}
+
+// contactPageFetcher fetches the page of contacts at the offset, returning the offset of the following page and whether
+// there is one
+type contactPageFetcher func(ctx context.Context, offset ContactOffset) ([]Contact, ContactOffset, bool, error)
+
+// ContactIterator walks through a listing of contacts, fetching pages from Hubspot as they are needed. It should be used
+// like a bufio.Scanner:
+//
+//	it := client.ListContacts(ctx, nil)
+//	for it.Next() {
+//		contact := it.Contact()
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle the error
+//	}
+type ContactIterator struct {
+	pager
+	page []Contact
+}
+
+func newContactIterator(ctx context.Context, offset ContactOffset, fetch contactPageFetcher) *ContactIterator {
+	it := &ContactIterator{}
+	it.pager = newCursorPager(ctx, offset, func(ctx context.Context, cursor interface{}) (int, interface{}, bool, error) {
+		page, next, hasMore, err := fetch(ctx, cursor.(ContactOffset))
+		if err != nil {
+			return 0, next, false, err
+		}
+		it.page = page
+		return len(page), next, hasMore, nil
+	})
+	return it
+}
+
+// Next advances to the next contact, fetching another page if needed. It returns false when there are no more contacts
+// or an error occurred, which is then available from Err.
+func (it *ContactIterator) Next() bool {
+	return it.next()
+}
+
+// Contact returns the current contact. It is only valid after a call to Next returns true.
+func (it *ContactIterator) Contact() Contact {
+	if i := it.current(); i >= 0 {
+		return it.page[i]
+	}
+	return Contact{}
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *ContactIterator) Err() error {
+	return it.err
+}
+
+// Offset returns a cursor to resume the listing from with ContactListOptions.Offset. See pager.cursor for where it
+// points.
+func (it *ContactIterator) Offset() ContactOffset {
+	offset, _ := it.cursor().(ContactOffset)
+	return offset
+}
+
+// ListContacts walks every contact in the portal, oldest first
+//
+// API Doc: https://developers.hubspot.com/docs/methods/contacts/get_contacts
+func (c *Client) ListContacts(ctx context.Context, opts *ContactListOptions) *ContactIterator {
+	return c.listContacts(ctx, EndpointListContacts, opts)
+}
+
+// ListContacts calls Client.ListContacts on the DefaultClient with a background context
+func ListContacts(opts *ContactListOptions) *ContactIterator {
+	return DefaultClient().ListContacts(context.Background(), opts)
+}
+
+// ListRecentlyUpdatedContacts walks the contacts updated in the last 30 days, most recently updated first
+//
+// API Doc: https://developers.hubspot.com/docs/methods/contacts/get_recently_updated_contacts
+func (c *Client) ListRecentlyUpdatedContacts(ctx context.Context, opts *ContactListOptions) *ContactIterator {
+	return c.listContacts(ctx, EndpointListRecentlyUpdatedContacts, opts)
+}
+
+// ListRecentlyUpdatedContacts calls Client.ListRecentlyUpdatedContacts on the DefaultClient with a background context
+func ListRecentlyUpdatedContacts(opts *ContactListOptions) *ContactIterator {
+	return DefaultClient().ListRecentlyUpdatedContacts(context.Background(), opts)
+}
+
+// ListRecentlyCreatedContacts walks the contacts created in the last 30 days, most recently created first
+//
+// API Doc: https://developers.hubspot.com/docs/methods/contacts/get_recently_created_contacts
+func (c *Client) ListRecentlyCreatedContacts(ctx context.Context, opts *ContactListOptions) *ContactIterator {
+	return c.listContacts(ctx, EndpointListRecentlyCreatedContacts, opts)
+}
+
+// ListRecentlyCreatedContacts calls Client.ListRecentlyCreatedContacts on the DefaultClient with a background context
+func ListRecentlyCreatedContacts(opts *ContactListOptions) *ContactIterator {
+	return DefaultClient().ListRecentlyCreatedContacts(context.Background(), opts)
+}
+
+// listContacts builds an iterator over one of the contact listing endpoints
+func (c *Client) listContacts(ctx context.Context, endpoint string, opts *ContactListOptions) *ContactIterator {
+	if opts == nil {
+		opts = &ContactListOptions{}
+	}
+	count := pageCount(opts.Count, contactListPageSize)
+	return newContactIterator(ctx, opts.Offset, func(ctx context.Context, offset ContactOffset) ([]Contact, ContactOffset, bool, error) {
+		query := opts.ContactQueryOptions.queryParams()
+		query.Set("count", fmt.Sprintf("%d", count))
+		if offset.VIDOffset != 0 {
+			query.Set("vidOffset", fmt.Sprintf("%d", offset.VIDOffset))
+		}
+		if offset.TimeOffset != 0 {
+			query.Set("timeOffset", fmt.Sprintf("%d", offset.TimeOffset))
+		}
+
+		result := contactListResponse{}
+		_, err := c.prepareCall(ctx, endpoint, map[string]string{}, query, &result)
+		if err != nil {
+			if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+				apiErr.SystemCode = CodeContactCouldNotBeListed
+				return nil, offset, false, apiErr
+			}
+			return nil, offset, false, err
+		}
+
+		contacts := make([]Contact, len(result.Contacts))
+		for i := range result.Contacts {
+			contacts[i].populateContactFields(result.Contacts[i])
+		}
+		return contacts, ContactOffset{
+			VIDOffset:  result.VIDOffset,
+			TimeOffset: result.TimeOffset,
+		}, result.HasMore, nil
+	})
+}
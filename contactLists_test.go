@@ -0,0 +1,115 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactListing(t *testing.T) {
+	queries := []url.Values{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("vidOffset") {
+		case "":
+			fmt.Fprint(w, `{"contacts": [{"vid": 1}, {"vid": 2}], "has-more": true, "vid-offset": 2}`)
+		case "2":
+			// an empty page in the middle is skipped over
+			fmt.Fprint(w, `{"contacts": [], "has-more": true, "vid-offset": 3}`)
+		case "3":
+			fmt.Fprint(w, `{"contacts": [{"vid": 3, "properties": {"email": {"value": "three@test.com"}}}], "has-more": false, "vid-offset": 3}`)
+		}
+	})
+	defer server.Close()
+
+	it := client.ListContacts(context.Background(), &ContactListOptions{
+		ContactQueryOptions: ContactQueryOptions{Properties: []string{"email"}},
+	})
+	assert.Equal(t, ContactOffset{}, it.Offset())
+	vids := []int64{}
+	saved := ContactOffset{VIDOffset: -1}
+	for it.Next() {
+		vids = append(vids, it.Contact().VID)
+		if it.Contact().VID == 1 {
+			// part way through a page, resuming starts from that page
+			assert.Equal(t, ContactOffset{}, it.Offset())
+		}
+		if it.Contact().VID == 2 {
+			// the last contact of a page may not have been handled yet, so resuming still starts from that page
+			saved = it.Offset()
+		}
+	}
+	require.Nil(t, it.Err())
+	assert.Equal(t, []int64{1, 2, 3}, vids)
+	assert.Equal(t, ContactOffset{VIDOffset: 3}, it.Offset())
+	require.Len(t, queries, 3)
+	assert.Equal(t, "100", queries[0].Get("count"))
+	assert.Equal(t, "email", queries[0].Get("property"))
+	assert.False(t, it.Next())
+
+	// resuming from the offset saved on the last contact of the first page reads that contact again
+	queries = []url.Values{}
+	it = client.ListContacts(context.Background(), &ContactListOptions{Offset: saved})
+	vids = []int64{}
+	for it.Next() {
+		vids = append(vids, it.Contact().VID)
+	}
+	require.Nil(t, it.Err())
+	assert.Equal(t, []int64{1, 2, 3}, vids)
+	assert.Equal(t, "", queries[0].Get("vidOffset"))
+
+	// resuming from a saved offset
+	queries = []url.Values{}
+	it = client.ListContacts(context.Background(), &ContactListOptions{Count: 10, Offset: ContactOffset{VIDOffset: 3}})
+	require.True(t, it.Next())
+	assert.Equal(t, "three@test.com", it.Contact().Email)
+	assert.False(t, it.Next())
+	assert.Equal(t, "10", queries[0].Get("count"))
+	assert.Equal(t, "3", queries[0].Get("vidOffset"))
+}
+
+func TestContactListingRecentAndErrors(t *testing.T) {
+	status := http.StatusOK
+	paths := []string{}
+	queries := []url.Values{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if r.URL.Query().Get("timeOffset") == "" {
+			fmt.Fprint(w, `{"contacts": [{"vid": 9}], "has-more": true, "vid-offset": 9, "time-offset": 1546300800000}`)
+			return
+		}
+		fmt.Fprint(w, `{"contacts": [{"vid": 8}], "has-more": false, "vid-offset": 8, "time-offset": 1546300700000}`)
+	})
+	defer server.Close()
+
+	it := client.ListRecentlyUpdatedContacts(context.Background(), nil)
+	require.True(t, it.Next())
+	require.True(t, it.Next())
+	assert.Equal(t, int64(8), it.Contact().VID)
+	assert.False(t, it.Next())
+	assert.Equal(t, "/contacts/v1/lists/recently_updated/contacts/recent", paths[0])
+	assert.Equal(t, "1546300800000", queries[1].Get("timeOffset"))
+	assert.Equal(t, "9", queries[1].Get("vidOffset"))
+
+	it = client.ListRecentlyCreatedContacts(context.Background(), nil)
+	require.True(t, it.Next())
+	assert.Equal(t, "/contacts/v1/lists/all/contacts/recent", paths[2])
+
+	status = http.StatusBadRequest
+	it = client.ListContacts(context.Background(), nil)
+	assert.False(t, it.Next())
+	require.NotNil(t, it.Err())
+	apiErr, cOK := it.Err().(APIError)
+	require.True(t, cOK)
+	assert.Equal(t, CodeContactCouldNotBeListed, apiErr.SystemCode)
+	assert.Equal(t, Contact{}, it.Contact())
+}
@@ -0,0 +1,111 @@
+package hubspot
+
+import "time"
+
+// ContactPropertyValue is a property of a contact as returned by Hubspot. Versions is only filled in when the property
+// history was asked for, and is ordered newest first.
+type ContactPropertyValue struct {
+	Value    string
+	Versions []ContactPropertyVersion
+}
+
+// ContactPropertyVersion is a single value a property has had, along with where it came from
+type ContactPropertyVersion struct {
+	Value string
+	// SourceType is what set the value, such as "CONTACTS_WEB", "FORM", "IMPORT" or "API"
+	SourceType  string
+	SourceID    string
+	SourceLabel string
+	Timestamp   time.Time
+	Selected    bool
+}
+
+// ContactIdentityProfile groups the identities Hubspot knows a contact by. A contact that has been merged has a
+// profile for each of the merged contacts.
+type ContactIdentityProfile struct {
+	VID            int64
+	SavedAt        time.Time
+	DeletedChanged time.Time
+	Identities     []ContactIdentity
+}
+
+// ContactIdentity is a single identity of a contact, such as an email address or a tracking cookie
+type ContactIdentity struct {
+	// Type is the kind of identity, such as "EMAIL" or "LEAD_GUID"
+	Type      string
+	Value     string
+	Timestamp time.Time
+	IsPrimary bool
+}
+
+// ContactFormSubmission is a form the contact has filled in
+type ContactFormSubmission struct {
+	ConversionID string
+	Timestamp    time.Time
+	FormID       string
+	PortalID     int64
+	PageURL      string
+	PageTitle    string
+	Title        string
+	FormType     string
+}
+
+// ContactListMembership is a contact list the contact is in
+type ContactListMembership struct {
+	StaticListID   int64
+	InternalListID int64
+	Timestamp      time.Time
+	IsMember       bool
+}
+
+// HasProperty reports whether Hubspot returned the property for the contact
+func (contact *Contact) HasProperty(name string) bool {
+	_, found := contact.Properties[name]
+	return found
+}
+
+// Property returns the current value of the property, or a blank string if Hubspot did not return it
+func (contact *Contact) Property(name string) string {
+	return contact.Properties[name].Value
+}
+
+// PropertyInt returns the current value of a number property as an integer. A blank or missing property is 0.
+func (contact *Contact) PropertyInt(name string) (int64, error) {
+	return propertyInt(name, contact.Property(name))
+}
+
+// PropertyFloat returns the current value of a number property. A blank or missing property is 0.
+func (contact *Contact) PropertyFloat(name string) (float64, error) {
+	return propertyFloat(name, contact.Property(name))
+}
+
+// PropertyBool returns the current value of a checkbox property. A blank or missing property is false.
+func (contact *Contact) PropertyBool(name string) (bool, error) {
+	return propertyBool(name, contact.Property(name))
+}
+
+// PropertyTime returns the current value of a date or datetime property. A blank or missing property is the zero time.
+func (contact *Contact) PropertyTime(name string) (time.Time, error) {
+	return propertyTime(name, contact.Property(name))
+}
+
+// PropertyOptions returns the selected options of a multi-select property
+func (contact *Contact) PropertyOptions(name string) []string {
+	return propertyOptions(contact.Property(name))
+}
+
+// PropertyVersions returns the history of the property, newest first. It is only available when the contact was fetched
+// with ContactPropertyModeValueAndHistory.
+func (contact *Contact) PropertyVersions(name string) []ContactPropertyVersion {
+	return contact.Properties[name].Versions
+}
+
+// UnmarshalProperties fills in your own tagged struct from the contact's properties, as described in the package level
+// UnmarshalProperties. It is the reverse of SetProperties.
+func (contact *Contact) UnmarshalProperties(v interface{}) error {
+	values := make(map[string]string, len(contact.Properties))
+	for name, property := range contact.Properties {
+		values[name] = property.Value
+	}
+	return UnmarshalProperties(values, v)
+}
@@ -0,0 +1,112 @@
+package hubspot
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactKeepsEverythingReturned(t *testing.T) {
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"vid": 42,
+			"properties": {
+				"firstname": {"value": "Test", "versions": [
+					{"value": "Test", "source-type": "CONTACTS_WEB", "source-id": "userId:123", "source-label": null, "timestamp": 1577836800000, "selected": false},
+					{"value": "Tset", "source-type": "FORM", "source-id": 77, "timestamp": "1577750400000", "selected": false}
+				]},
+				"dog_count": {"value": "2.0"},
+				"annual_spend": {"value": 129.99},
+				"has_collar": {"value": "true"},
+				"renews_on": {"value": "1577836800000"},
+				"toys": {"value": "ball;rope"},
+				"bad_number": {"value": "lots"}
+			},
+			"identity-profiles": [{
+				"vid": 42,
+				"saved-at-timestamp": 1577836800000,
+				"deleted-changed-timestamp": 0,
+				"identities": [
+					{"type": "EMAIL", "value": "test@test.com", "timestamp": 1577836800000, "is-primary": true},
+					{"type": "LEAD_GUID", "value": "abc-123", "timestamp": 1577836800000}
+				]
+			}],
+			"form-submissions": [{
+				"conversion-id": "conv-1", "timestamp": 1577836800000, "form-id": "form-1", "portal-id": 62515,
+				"page-url": "https://www.wagz.com/signup", "title": "Sign Up", "form-type": "HUBSPOT"
+			}],
+			"list-memberships": [{"static-list-id": 7, "internal-list-id": 2147483643, "timestamp": 1577836800000, "vid": 42, "is-member": true}]
+		}`))
+	})
+	defer server.Close()
+
+	contact, err := client.GetContactByVID(context.Background(), 42, &ContactQueryOptions{
+		PropertyMode:        ContactPropertyModeValueAndHistory,
+		ShowListMemberships: true,
+	})
+	require.Nil(t, err)
+	jan1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// the custom properties are kept, not just the fields on the contact
+	assert.Equal(t, "Test", contact.FirstName)
+	assert.True(t, contact.HasProperty("dog_count"))
+	assert.False(t, contact.HasProperty("missing"))
+	assert.Equal(t, "", contact.Property("missing"))
+	dogs, err := contact.PropertyInt("dog_count")
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), dogs)
+	spend, err := contact.PropertyFloat("annual_spend")
+	require.Nil(t, err)
+	assert.Equal(t, 129.99, spend)
+	collar, err := contact.PropertyBool("has_collar")
+	require.Nil(t, err)
+	assert.True(t, collar)
+	renews, err := contact.PropertyTime("renews_on")
+	require.Nil(t, err)
+	assert.True(t, jan1.Equal(renews))
+	assert.Equal(t, []string{"ball", "rope"}, contact.PropertyOptions("toys"))
+	assert.Equal(t, []string{}, contact.PropertyOptions("missing"))
+	_, err = contact.PropertyInt("bad_number")
+	require.NotNil(t, err)
+	assert.Equal(t, CodePropertyCouldNotBeConverted, err.(APIError).SystemCode)
+
+	versions := contact.PropertyVersions("firstname")
+	require.Len(t, versions, 2)
+	assert.Equal(t, "CONTACTS_WEB", versions[0].SourceType)
+	assert.Equal(t, "userId:123", versions[0].SourceID)
+	assert.True(t, jan1.Equal(versions[0].Timestamp))
+	assert.Equal(t, "77", versions[1].SourceID)
+	assert.Equal(t, "Tset", versions[1].Value)
+
+	require.Len(t, contact.IdentityProfiles, 1)
+	assert.True(t, contact.IdentityProfiles[0].DeletedChanged.IsZero())
+	require.Len(t, contact.IdentityProfiles[0].Identities, 2)
+	assert.True(t, contact.IdentityProfiles[0].Identities[0].IsPrimary)
+	assert.Equal(t, "LEAD_GUID", contact.IdentityProfiles[0].Identities[1].Type)
+
+	require.Len(t, contact.FormSubmissions, 1)
+	assert.Equal(t, "form-1", contact.FormSubmissions[0].FormID)
+	assert.Equal(t, int64(62515), contact.FormSubmissions[0].PortalID)
+	assert.True(t, jan1.Equal(contact.FormSubmissions[0].Timestamp))
+
+	require.Len(t, contact.ListMemberships, 1)
+	assert.Equal(t, int64(7), contact.ListMemberships[0].StaticListID)
+	assert.True(t, contact.ListMemberships[0].IsMember)
+
+	// and can be read in to a tagged struct
+	custom := struct {
+		Dogs int      `hubspot:"dog_count"`
+		Toys []string `hubspot:"toys"`
+	}{}
+	require.Nil(t, contact.UnmarshalProperties(&custom))
+	assert.Equal(t, 2, custom.Dogs)
+	assert.Equal(t, []string{"ball", "rope"}, custom.Toys)
+
+	// what came back from Hubspot is not sent back when saving
+	assert.Equal(t, []map[string]string{{"property": "firstname", "value": "Test"}}, contact.convertContactToProperties())
+}
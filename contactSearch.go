@@ -0,0 +1,101 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SortOrder is the direction results are sorted in
+type SortOrder string
+
+// The sort orders accepted by Hubspot
+const (
+	SortOrderAscending  SortOrder = "ASC"
+	SortOrderDescending SortOrder = "DESC"
+)
+
+// ContactSearchOptions controls a contact search. A nil options returns every property, sorted by Hubspot's relevance.
+type ContactSearchOptions struct {
+	// Properties limits the returned properties to the named ones
+	Properties []string
+	// SortBy is the name of the property to sort by, such as "lastmodifieddate"
+	SortBy    string
+	SortOrder SortOrder
+	// Count is the number of contacts fetched per page, up to 100. It defaults to 100.
+	Count int
+	// Offset resumes a search from a cursor returned by ContactIterator.Offset
+	Offset ContactOffset
+}
+
+// contactSearchResponse is a single page of search results as returned by Hubspot
+type contactSearchResponse struct {
+	Contacts []contactResponse `json:"contacts"`
+	HasMore  bool              `json:"has-more"`
+	Offset   int64             `json:"offset"`
+	Total    int64             `json:"total"`
+}
+
+// SearchContacts searches for contacts whose email, name, phone number or company contains the query. The results are
+// returned as a ContactIterator, the same as the contact listings.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/contacts/search_contacts
+func (c *Client) SearchContacts(ctx context.Context, query string, opts *ContactSearchOptions) *ContactIterator {
+	if strings.TrimSpace(query) == "" {
+		return &ContactIterator{
+			pager: pager{
+				err: APIError{
+					HTTPCode:   http.StatusBadRequest,
+					SystemCode: CodeContactSearchNoQuery,
+					Message:    "you must specify something to search for",
+					Body:       nil,
+				},
+			},
+		}
+	}
+	if opts == nil {
+		opts = &ContactSearchOptions{}
+	}
+	count := pageCount(opts.Count, contactListPageSize)
+	return newContactIterator(ctx, opts.Offset, func(ctx context.Context, offset ContactOffset) ([]Contact, ContactOffset, bool, error) {
+		params := url.Values{
+			"q":     {query},
+			"count": {fmt.Sprintf("%d", count)},
+		}
+		if len(opts.Properties) > 0 {
+			params["property"] = opts.Properties
+		}
+		if offset.Offset != 0 {
+			params["offset"] = []string{fmt.Sprintf("%d", offset.Offset)}
+		}
+		if opts.SortBy != "" {
+			params["sort"] = []string{opts.SortBy}
+		}
+		if opts.SortOrder != "" {
+			params["order"] = []string{string(opts.SortOrder)}
+		}
+
+		result := contactSearchResponse{}
+		_, err := c.prepareCall(ctx, EndpointSearchContacts, map[string]string{}, params, &result)
+		if err != nil {
+			if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+				apiErr.SystemCode = CodeContactCouldNotBeSearched
+				return nil, offset, false, apiErr
+			}
+			return nil, offset, false, err
+		}
+
+		contacts := make([]Contact, len(result.Contacts))
+		for i := range result.Contacts {
+			contacts[i].populateContactFields(result.Contacts[i])
+		}
+		return contacts, ContactOffset{Offset: result.Offset}, result.HasMore, nil
+	})
+}
+
+// SearchContacts calls Client.SearchContacts on the DefaultClient with a background context
+func SearchContacts(query string, opts *ContactSearchOptions) *ContactIterator {
+	return DefaultClient().SearchContacts(context.Background(), query, opts)
+}
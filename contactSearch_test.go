@@ -0,0 +1,61 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactSearch(t *testing.T) {
+	queries := []url.Values{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/contacts/v1/search/query", r.URL.Path)
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprint(w, `{"query": "wagz", "offset": 2, "has-more": true, "total": 3, "contacts": [
+				{"vid": 1, "properties": {"company": {"value": "Wagz"}}}, {"vid": 2}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"query": "wagz", "offset": 3, "has-more": false, "total": 3, "contacts": [{"vid": 3}]}`)
+	})
+	defer server.Close()
+
+	it := client.SearchContacts(context.Background(), " ", nil)
+	assert.False(t, it.Next())
+	require.NotNil(t, it.Err())
+	assert.Equal(t, CodeContactSearchNoQuery, it.Err().(APIError).SystemCode)
+	assert.Empty(t, queries)
+
+	it = client.SearchContacts(context.Background(), "wagz", &ContactSearchOptions{
+		Properties: []string{"company", "email"},
+		SortBy:     "lastmodifieddate",
+		SortOrder:  SortOrderDescending,
+		Count:      2,
+	})
+	vids := []int64{}
+	for it.Next() {
+		vids = append(vids, it.Contact().VID)
+	}
+	require.Nil(t, it.Err())
+	assert.Equal(t, []int64{1, 2, 3}, vids)
+	assert.Equal(t, ContactOffset{Offset: 3}, it.Offset())
+	require.Len(t, queries, 2)
+	assert.Equal(t, "wagz", queries[0].Get("q"))
+	assert.Equal(t, "2", queries[0].Get("count"))
+	assert.Equal(t, []string{"company", "email"}, queries[0]["property"])
+	assert.Equal(t, "lastmodifieddate", queries[0].Get("sort"))
+	assert.Equal(t, "DESC", queries[0].Get("order"))
+	assert.Equal(t, "2", queries[1].Get("offset"))
+
+	// resuming from a saved offset
+	it = client.SearchContacts(context.Background(), "wagz", &ContactSearchOptions{Offset: ContactOffset{Offset: 2}})
+	require.True(t, it.Next())
+	assert.Equal(t, int64(3), it.Contact().VID)
+	assert.Equal(t, "2", queries[2].Get("offset"))
+}
@@ -1,11 +1,12 @@
 package hubspot
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
-
-	"github.com/fatih/structs"
 )
 
 // ContactProperty defines the structure on the input of any 'Additional Properties' in to a contact entry
@@ -16,27 +17,161 @@ type ContactProperty struct {
 
 // Contact is an individual person that you would like to create or update in Hubspot. Email is the only required field.
 // By default, the VID field will be 0. After a create call, the VID will be filled in for you.
-// Additional porperties are tricky
+//
+// The `hubspot` tags map each field to its Hubspot property. Any other properties can be sent with AdditionalProperties,
+// or with SetProperties from your own tagged struct.
 type Contact struct {
-	VID                  int64
-	Email                string
-	FirstName            string
-	LastName             string
-	Website              string
-	Company              string
-	Phone                string
-	Address              string
-	City                 string
-	State                string
-	Zip                  string
-	AdditionalProperties *[]ContactProperty
+	VID                  int64              `hubspot:"-"`
+	Email                string             `hubspot:"email,omitempty"`
+	FirstName            string             `hubspot:"firstname,omitempty"`
+	LastName             string             `hubspot:"lastname,omitempty"`
+	Website              string             `hubspot:"website,omitempty"`
+	Company              string             `hubspot:"company,omitempty"`
+	Phone                string             `hubspot:"phone,omitempty"`
+	Address              string             `hubspot:"address,omitempty"`
+	City                 string             `hubspot:"city,omitempty"`
+	State                string             `hubspot:"state,omitempty"`
+	Zip                  string             `hubspot:"zip,omitempty"`
+	AdditionalProperties *[]ContactProperty `hubspot:"-"`
+	// ClearProperties lists properties to blank out in Hubspot. Empty fields are not sent, so without this there is no
+	// way to tell "leave the phone number alone" from "remove the phone number". A property listed here is cleared even
+	// if a field or AdditionalProperties sets it.
+	ClearProperties []string `hubspot:"-"`
+
+	// The remaining fields are filled in when a contact is fetched from Hubspot and are not sent back when saving it.
+	// Properties holds every property Hubspot returned, not just the ones with a field above; the Property accessors
+	// read from it. The versions of each property are only returned when asked for with
+	// ContactPropertyModeValueAndHistory.
+	Properties       map[string]ContactPropertyValue `hubspot:"-"`
+	IdentityProfiles []ContactIdentityProfile        `hubspot:"-"`
+	FormSubmissions  []ContactFormSubmission         `hubspot:"-"`
+	ListMemberships  []ContactListMembership         `hubspot:"-"`
+}
+
+// ContactPropertyMode controls whether Hubspot returns just the current value of each property or its history as well
+type ContactPropertyMode string
+
+// ContactFormSubmissionMode controls which form submissions Hubspot returns with a contact
+type ContactFormSubmissionMode string
+
+// The property and form submission modes accepted by the contact lookups
+const (
+	ContactPropertyModeValueOnly       ContactPropertyMode = "value_only"
+	ContactPropertyModeValueAndHistory ContactPropertyMode = "value_and_history"
+
+	ContactFormSubmissionModeAll    ContactFormSubmissionMode = "all"
+	ContactFormSubmissionModeNone   ContactFormSubmissionMode = "none"
+	ContactFormSubmissionModeNewest ContactFormSubmissionMode = "newest"
+	ContactFormSubmissionModeOldest ContactFormSubmissionMode = "oldest"
+)
+
+// contactBatchSize is the most identifiers Hubspot will accept in a single batch lookup
+const contactBatchSize = 100
+
+// ContactQueryOptions controls what Hubspot includes when looking up contacts. A nil options uses Hubspot's defaults,
+// which is every property with no history, all form submissions and no list memberships.
+type ContactQueryOptions struct {
+	// Properties limits the returned properties to the named ones
+	Properties          []string
+	PropertyMode        ContactPropertyMode
+	FormSubmissionMode  ContactFormSubmissionMode
+	ShowListMemberships bool
+}
+
+// queryParams converts the options in to the query string Hubspot expects
+func (opts *ContactQueryOptions) queryParams() url.Values {
+	params := url.Values{}
+	if opts == nil {
+		return params
+	}
+	for _, property := range opts.Properties {
+		params.Add("property", property)
+	}
+	if opts.PropertyMode != "" {
+		params.Set("propertyMode", string(opts.PropertyMode))
+	}
+	if opts.FormSubmissionMode != "" {
+		params.Set("formSubmissionMode", string(opts.FormSubmissionMode))
+	}
+	if opts.ShowListMemberships {
+		params.Set("showListMemberships", "true")
+	}
+	return params
+}
+
+// contactResponse is a contact as returned by Hubspot
+type contactResponse struct {
+	VID              int64                              `json:"vid"`
+	Properties       map[string]contactPropertyResponse `json:"properties"`
+	IdentityProfiles []contactIdentityProfileResponse   `json:"identity-profiles"`
+	FormSubmissions  []contactFormSubmissionResponse    `json:"form-submissions"`
+	ListMemberships  []contactListMembershipResponse    `json:"list-memberships"`
+}
+
+// contactIdentityProfileResponse holds the identities, such as email addresses, Hubspot knows a contact by
+type contactIdentityProfileResponse struct {
+	VID                     int64                     `json:"vid"`
+	SavedAtTimestamp        timestamp                 `json:"saved-at-timestamp"`
+	DeletedChangedTimestamp timestamp                 `json:"deleted-changed-timestamp"`
+	Identities              []contactIdentityResponse `json:"identities"`
+}
+
+// contactIdentityResponse is a single identity of a contact
+type contactIdentityResponse struct {
+	Type      string    `json:"type"`
+	Value     string    `json:"value"`
+	Timestamp timestamp `json:"timestamp"`
+	IsPrimary bool      `json:"is-primary"`
+}
+
+// contactPropertyResponse is a single property of a contact as returned by Hubspot
+type contactPropertyResponse struct {
+	Value    PropertyValue                    `json:"value"`
+	Versions []contactPropertyVersionResponse `json:"versions"`
+}
+
+// contactPropertyVersionResponse is a single version in the history of a property
+type contactPropertyVersionResponse struct {
+	Value       PropertyValue `json:"value"`
+	SourceType  string        `json:"source-type"`
+	SourceID    PropertyValue `json:"source-id"`
+	SourceLabel string        `json:"source-label"`
+	Timestamp   timestamp     `json:"timestamp"`
+	Selected    bool          `json:"selected"`
+}
+
+// contactFormSubmissionResponse is a form the contact has filled in
+type contactFormSubmissionResponse struct {
+	ConversionID string    `json:"conversion-id"`
+	Timestamp    timestamp `json:"timestamp"`
+	FormID       string    `json:"form-id"`
+	PortalID     int64     `json:"portal-id"`
+	PageURL      string    `json:"page-url"`
+	PageTitle    string    `json:"page-title"`
+	Title        string    `json:"title"`
+	FormType     string    `json:"form-type"`
+}
+
+// contactListMembershipResponse is a list the contact is in
+type contactListMembershipResponse struct {
+	StaticListID   int64     `json:"static-list-id"`
+	InternalListID int64     `json:"internal-list-id"`
+	Timestamp      timestamp `json:"timestamp"`
+	VID            int64     `json:"vid"`
+	IsMember       bool      `json:"is-member"`
+}
+
+// createOrUpdateContactResponse is returned by Hubspot when a contact is created or updated
+type createOrUpdateContactResponse struct {
+	VID   int64 `json:"vid"`
+	IsNew bool  `json:"isNew"`
 }
 
 // CreateOrUpdateContact creates or updates a contact using an email address and additional properties. If the VID is 0, it
 // is assumed that the user has not been created or updated yet.
 //
 // API Doc: https://developers.hubspot.com/docs/methods/contacts/create_or_update
-func CreateOrUpdateContact(contact *Contact) error {
+func (c *Client) CreateOrUpdateContact(ctx context.Context, contact *Contact) error {
 	// if email is blank, return an error
 	if contact.Email == "" {
 		return APIError{
@@ -53,30 +188,146 @@ func CreateOrUpdateContact(contact *Contact) error {
 		"properties": props,
 	}
 
-	ret, err := prepareCall(EndpointCreateContact, map[string]string{
-		":email": contact.Email,
-	}, send)
+	result := createOrUpdateContactResponse{}
+	_, err := c.prepareCall(ctx, EndpointCreateContact, map[string]string{
+		":email": url.PathEscape(contact.Email),
+	}, send, &result)
 
 	if err != nil {
-		if apiErr, apiErrOK := err.(APIError); apiErrOK {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
 			apiErr.SystemCode = CodeContactCouldNotBeCreated
 			return apiErr
 		}
 		return err
 	}
-	if body, bodyOK := ret.Body.(map[string]interface{}); bodyOK {
-		if vidF, vidOK := body["vid"].(float64); vidOK {
-			contact.VID = int64(vidF)
-		}
+	if result.VID != 0 {
+		contact.VID = result.VID
 	}
 
 	return nil
 }
 
+// CreateOrUpdateContact calls Client.CreateOrUpdateContact on the DefaultClient with a background context
+func CreateOrUpdateContact(contact *Contact) error {
+	return DefaultClient().CreateOrUpdateContact(context.Background(), contact)
+}
+
+// UpdateContactByVID updates an existing contact using its VID. Unlike CreateOrUpdateContact, this can change the
+// contact's email address; set Email to the new address, or leave it blank to keep the current one. A contact that
+// doesn't exist returns a 404 with CodeContactNotFound.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/contacts/update_contact
+func (c *Client) UpdateContactByVID(ctx context.Context, contact *Contact) error {
+	if contact.VID == 0 {
+		return APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeContactVIDZero,
+			Message:    "the VID for the contact cannot be 0 when updating",
+			Body:       nil,
+		}
+	}
+	return c.updateContact(ctx, EndpointUpdateContactByVID, map[string]string{
+		":vid": fmt.Sprintf("%d", contact.VID),
+	}, contact)
+}
+
+// UpdateContactByVID calls Client.UpdateContactByVID on the DefaultClient with a background context
+func UpdateContactByVID(contact *Contact) error {
+	return DefaultClient().UpdateContactByVID(context.Background(), contact)
+}
+
+// UpdateContactByEmail updates the existing contact with the email address. Unlike CreateOrUpdateContact, a contact
+// that doesn't exist is not created and returns a 404 with CodeContactNotFound instead. To change the contact's email
+// address, set Email on the contact to the new one.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/contacts/update_contact-by-email
+func (c *Client) UpdateContactByEmail(ctx context.Context, email string, contact *Contact) error {
+	if email == "" || !strings.Contains(email, "@") {
+		return APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeContactNoEmail,
+			Message:    "you must specify the email address of the contact to update",
+			Body:       nil,
+		}
+	}
+	return c.updateContact(ctx, EndpointUpdateContactByEmail, map[string]string{
+		":email": url.PathEscape(email),
+	}, contact)
+}
+
+// UpdateContactByEmail calls Client.UpdateContactByEmail on the DefaultClient with a background context
+func UpdateContactByEmail(email string, contact *Contact) error {
+	return DefaultClient().UpdateContactByEmail(context.Background(), email, contact)
+}
+
+// updateContact sends the contact's properties to one of the update endpoints
+func (c *Client) updateContact(ctx context.Context, endpoint string, pathParams map[string]string, contact *Contact) error {
+	send := map[string]interface{}{
+		"properties": contact.convertContactToProperties(),
+	}
+	_, err := c.prepareCall(ctx, endpoint, pathParams, send, nil)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeContactNotFound
+				return apiErr
+			}
+			apiErr.SystemCode = CodeContactCouldNotBeUpdated
+			return apiErr
+		}
+	}
+	return err
+}
+
+// MergeContacts merges the secondary contact in to the primary one. The primary contact keeps its VID and the secondary
+// VID becomes an alias of it. Hubspot cannot undo a merge.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/contacts/merge-contacts
+func (c *Client) MergeContacts(ctx context.Context, primaryVID, secondaryVID int64) error {
+	if primaryVID == 0 || secondaryVID == 0 {
+		return APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeContactVIDZero,
+			Message:    "the VIDs of both contacts are required when merging",
+			Body:       nil,
+		}
+	}
+	if primaryVID == secondaryVID {
+		return APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeContactMergeSameVID,
+			Message:    "a contact cannot be merged in to itself",
+			Body:       nil,
+		}
+	}
+
+	_, err := c.prepareCall(ctx, EndpointMergeContacts, map[string]string{
+		":vid": fmt.Sprintf("%d", primaryVID),
+	}, map[string]interface{}{
+		"vidToMerge": secondaryVID,
+	}, nil)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeContactNotFound
+				return apiErr
+			}
+			apiErr.SystemCode = CodeContactCouldNotBeMerged
+			return apiErr
+		}
+	}
+	return err
+}
+
+// MergeContacts calls Client.MergeContacts on the DefaultClient with a background context
+func MergeContacts(primaryVID, secondaryVID int64) error {
+	return DefaultClient().MergeContacts(context.Background(), primaryVID, secondaryVID)
+}
+
 // DeleteContactByVID deletes a single contact by it's VID
 //
 // API Doc: https://developers.hubspot.com/docs/methods/contacts/delete_contact
-func DeleteContactByVID(vid int64) error {
+func (c *Client) DeleteContactByVID(ctx context.Context, vid int64) error {
 	if vid == 0 {
 		return APIError{
 			HTTPCode:   http.StatusBadRequest,
@@ -86,26 +337,31 @@ func DeleteContactByVID(vid int64) error {
 		}
 	}
 
-	_, err := prepareCall(EndpointDeleteContact, map[string]string{
+	_, err := c.prepareCall(ctx, EndpointDeleteContact, map[string]string{
 		":vid": fmt.Sprintf("%d", vid),
-	}, nil)
+	}, nil, nil)
 	if err != nil {
 		if apiErr, apiErrOK := err.(APIError); apiErrOK {
 			if apiErr.HTTPCode == 404 {
 				apiErr.SystemCode = CodeContactNotFound
 				return apiErr
 			}
-			apiErr.SystemCode = CodeContactCouldNotBeCreated
+			apiErr.SystemCode = CodeContactCouldNotBeDeleted
 			return apiErr
 		}
 	}
 	return err
 }
 
+// DeleteContactByVID calls Client.DeleteContactByVID on the DefaultClient with a background context
+func DeleteContactByVID(vid int64) error {
+	return DefaultClient().DeleteContactByVID(context.Background(), vid)
+}
+
 // GetContactByEmail gets a single contact by their email address
 //
 // API Doc: https://developers.hubspot.com/docs/methods/contacts/get_contact_by_email
-func GetContactByEmail(email string) (Contact, error) {
+func (c *Client) GetContactByEmail(ctx context.Context, email string) (Contact, error) {
 	contact := Contact{}
 	if email == "" || !strings.Contains(email, "@") {
 		return contact, APIError{
@@ -115,84 +371,371 @@ func GetContactByEmail(email string) (Contact, error) {
 			Body:       nil,
 		}
 	}
-	res, err := prepareCall(EndpointGetContact, map[string]string{
-		":email": email,
+	return c.getContact(ctx, EndpointGetContact, map[string]string{
+		":email": url.PathEscape(email),
 	}, nil)
-	if err != nil {
-		if apiErr, apiErrOK := err.(APIError); apiErrOK {
-			if apiErr.HTTPCode == 404 {
-				apiErr.SystemCode = CodeContactNotFound
-				return contact, apiErr
+}
+
+// GetContactByEmail calls Client.GetContactByEmail on the DefaultClient with a background context
+func GetContactByEmail(email string) (Contact, error) {
+	return DefaultClient().GetContactByEmail(context.Background(), email)
+}
+
+// GetContactByVID gets a single contact by their VID. The options may be nil.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/contacts/get_contact
+func (c *Client) GetContactByVID(ctx context.Context, vid int64, opts *ContactQueryOptions) (Contact, error) {
+	if vid == 0 {
+		return Contact{}, APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeContactVIDZero,
+			Message:    "the VID for the contact cannot be 0",
+			Body:       nil,
+		}
+	}
+	return c.getContact(ctx, EndpointGetContactByVID, map[string]string{
+		":vid": fmt.Sprintf("%d", vid),
+	}, opts)
+}
+
+// GetContactByVID calls Client.GetContactByVID on the DefaultClient with a background context
+func GetContactByVID(vid int64, opts *ContactQueryOptions) (Contact, error) {
+	return DefaultClient().GetContactByVID(context.Background(), vid, opts)
+}
+
+// GetContactByUTK gets a single contact by the user token stored in their hubspotutk tracking cookie. The options
+// may be nil.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/contacts/get_contact_by_utk
+func (c *Client) GetContactByUTK(ctx context.Context, utk string, opts *ContactQueryOptions) (Contact, error) {
+	if utk == "" {
+		return Contact{}, APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeContactNoUTK,
+			Message:    "you must specify a user token for that contact",
+			Body:       nil,
+		}
+	}
+	return c.getContact(ctx, EndpointGetContactByUTK, map[string]string{
+		":utk": url.PathEscape(utk),
+	}, opts)
+}
+
+// GetContactByUTK calls Client.GetContactByUTK on the DefaultClient with a background context
+func GetContactByUTK(utk string, opts *ContactQueryOptions) (Contact, error) {
+	return DefaultClient().GetContactByUTK(context.Background(), utk, opts)
+}
+
+// GetContactsByEmails gets several contacts at once, keyed by the email address they were requested with. Emails that
+// do not match a contact are left out of the map. Hubspot limits each request to 100 emails, so larger slices are sent
+// in several requests. The options may be nil.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/contacts/get_batch_by_email
+func (c *Client) GetContactsByEmails(ctx context.Context, emails []string, opts *ContactQueryOptions) (map[string]Contact, error) {
+	contacts := map[string]Contact{}
+	if len(emails) == 0 {
+		return contacts, contactBatchMissingDataError("email addresses")
+	}
+	for _, email := range emails {
+		if email == "" || !strings.Contains(email, "@") {
+			return contacts, APIError{
+				HTTPCode:   http.StatusBadRequest,
+				SystemCode: CodeContactNoEmail,
+				Message:    fmt.Sprintf("%q is not a valid email address", email),
+				Body:       nil,
+			}
+		}
+	}
+	err := c.getContactBatches(ctx, EndpointGetContactsByEmails, "email", emails, opts, func(key string, result contactResponse) {
+		contact := Contact{}
+		contact.populateContactFields(result)
+		// Hubspot keys the results by VID and matches secondary emails too, so look for the ones we asked for
+		for _, email := range emails {
+			if result.hasEmail(email) {
+				contacts[email] = contact
 			}
-			apiErr.SystemCode = CodeGeneralError
-			return contact, apiErr
-		}
-	}
-	// this part is fun; we don't want to worry about all of the properties,
-	// so let's loop and figure it out
-	fields := res.Body.(map[string]interface{})
-	contact.populateContactFields(fields)
-	return contact, err
-}
-
-func (contact *Contact) populateContactFields(fields map[string]interface{}) {
-	for k, v := range fields {
-		if k == "vid" {
-			vidF := v.(float64)
-			contact.VID = int64(vidF)
-		}
-		if k == "properties" {
-			// this is the really big one
-			// we need to loop over all of the possible proprties; this is a map of string/interfaces
-			properties := v.(map[string]interface{})
-			for pK, pV := range properties {
-				vals := pV.(map[string]interface{})
-				switch pK {
-				case "firstname":
-					contact.FirstName = vals["value"].(string)
-				case "lastname":
-					contact.LastName = vals["value"].(string)
-				case "email":
-					contact.Email = vals["value"].(string)
-				case "address":
-					contact.Address = vals["value"].(string)
-				case "city":
-					contact.City = vals["value"].(string)
-				case "state":
-					contact.State = vals["value"].(string)
-				case "zip":
-					contact.Zip = vals["value"].(string)
-				case "company":
-					contact.Company = vals["value"].(string)
-				case "phone":
-					contact.Phone = vals["value"].(string)
-				case "website":
-					contact.Website = vals["value"].(string)
-				}
+		}
+	})
+	return contacts, err
+}
+
+// GetContactsByEmails calls Client.GetContactsByEmails on the DefaultClient with a background context
+func GetContactsByEmails(emails []string, opts *ContactQueryOptions) (map[string]Contact, error) {
+	return DefaultClient().GetContactsByEmails(context.Background(), emails, opts)
+}
+
+// GetContactsByVIDs gets several contacts at once, keyed by their VID. VIDs that do not match a contact are left out of
+// the map. Hubspot limits each request to 100 VIDs, so larger slices are sent in several requests. The options may be
+// nil.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/contacts/get_batch_by_vid
+func (c *Client) GetContactsByVIDs(ctx context.Context, vids []int64, opts *ContactQueryOptions) (map[int64]Contact, error) {
+	contacts := map[int64]Contact{}
+	if len(vids) == 0 {
+		return contacts, contactBatchMissingDataError("VIDs")
+	}
+	identifiers := make([]string, len(vids))
+	for i, vid := range vids {
+		if vid == 0 {
+			return contacts, APIError{
+				HTTPCode:   http.StatusBadRequest,
+				SystemCode: CodeContactVIDZero,
+				Message:    "the VID for the contact cannot be 0",
+				Body:       nil,
 			}
 		}
+		identifiers[i] = fmt.Sprintf("%d", vid)
 	}
+	err := c.getContactBatches(ctx, EndpointGetContactsByVIDs, "vid", identifiers, opts, func(key string, result contactResponse) {
+		contact := Contact{}
+		contact.populateContactFields(result)
+		if vid, parseErr := strconv.ParseInt(key, 10, 64); parseErr == nil {
+			contacts[vid] = contact
+			return
+		}
+		contacts[contact.VID] = contact
+	})
+	return contacts, err
 }
 
-func (contact *Contact) convertContactToProperties() []map[string]string {
-	props := []map[string]string{}
-	contactParsed := structs.Map(contact)
-	for k, v := range contactParsed {
-		// get the string value, if possible
-		if val, valOK := v.(string); valOK && v != "" {
-			props = append(props, map[string]string{
-				"property": strings.ToLower(k),
-				"value":    val,
+// GetContactsByVIDs calls Client.GetContactsByVIDs on the DefaultClient with a background context
+func GetContactsByVIDs(vids []int64, opts *ContactQueryOptions) (map[int64]Contact, error) {
+	return DefaultClient().GetContactsByVIDs(context.Background(), vids, opts)
+}
+
+// GetContactsByUTKs gets several contacts at once, keyed by the user token they were requested with. Tokens that do
+// not match a contact are left out of the map. Hubspot limits each request to 100 tokens, so larger slices are sent in
+// several requests. The options may be nil.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/contacts/get_batch_by_utk
+func (c *Client) GetContactsByUTKs(ctx context.Context, utks []string, opts *ContactQueryOptions) (map[string]Contact, error) {
+	contacts := map[string]Contact{}
+	if len(utks) == 0 {
+		return contacts, contactBatchMissingDataError("user tokens")
+	}
+	for _, utk := range utks {
+		if utk == "" {
+			return contacts, APIError{
+				HTTPCode:   http.StatusBadRequest,
+				SystemCode: CodeContactNoUTK,
+				Message:    "the user tokens cannot be blank",
+				Body:       nil,
+			}
+		}
+	}
+	err := c.getContactBatches(ctx, EndpointGetContactsByUTKs, "utk", utks, opts, func(key string, result contactResponse) {
+		contact := Contact{}
+		contact.populateContactFields(result)
+		contacts[key] = contact
+	})
+	return contacts, err
+}
+
+// GetContactsByUTKs calls Client.GetContactsByUTKs on the DefaultClient with a background context
+func GetContactsByUTKs(utks []string, opts *ContactQueryOptions) (map[string]Contact, error) {
+	return DefaultClient().GetContactsByUTKs(context.Background(), utks, opts)
+}
+
+// getContact fetches a single contact from one of the profile endpoints
+func (c *Client) getContact(ctx context.Context, endpoint string, pathParams map[string]string, opts *ContactQueryOptions) (Contact, error) {
+	contact := Contact{}
+	result := contactResponse{}
+	_, err := c.prepareCall(ctx, endpoint, pathParams, opts.queryParams(), &result)
+	if err != nil {
+		return contact, contactLookupError(err)
+	}
+	contact.populateContactFields(result)
+	return contact, nil
+}
+
+// getContactBatches looks up the identifiers in batches no larger than Hubspot allows, handing each contact found to
+// the add func along with the key Hubspot returned it under
+func (c *Client) getContactBatches(ctx context.Context, endpoint, param string, identifiers []string, opts *ContactQueryOptions, add func(key string, result contactResponse)) error {
+	for start := 0; start < len(identifiers); start += contactBatchSize {
+		end := start + contactBatchSize
+		if end > len(identifiers) {
+			end = len(identifiers)
+		}
+		query := opts.queryParams()
+		query[param] = identifiers[start:end]
+
+		results := map[string]contactResponse{}
+		_, err := c.prepareCall(ctx, endpoint, map[string]string{}, query, &results)
+		if err != nil {
+			return contactLookupError(err)
+		}
+		for key, result := range results {
+			add(key, result)
+		}
+	}
+	return nil
+}
+
+// contactLookupError sets the system code on an error from fetching contacts
+func contactLookupError(err error) error {
+	if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+		if apiErr.HTTPCode == 404 {
+			apiErr.SystemCode = CodeContactNotFound
+			return apiErr
+		}
+		apiErr.SystemCode = CodeGeneralError
+		return apiErr
+	}
+	// transport and decoding errors, including a cancelled context, have no body to parse
+	return err
+}
+
+// contactBatchMissingDataError is returned when a batch lookup is given nothing to look up
+func contactBatchMissingDataError(what string) APIError {
+	return APIError{
+		HTTPCode:   http.StatusBadRequest,
+		SystemCode: CodeContactBatchMissingData,
+		Message:    fmt.Sprintf("you must specify at least one of the %s to look up", what),
+		Body:       nil,
+	}
+}
+
+// hasEmail checks the contact's identities, and then its email property, for the email address
+func (result contactResponse) hasEmail(email string) bool {
+	for _, profile := range result.IdentityProfiles {
+		for _, identity := range profile.Identities {
+			if identity.Type == "EMAIL" && strings.EqualFold(identity.Value, email) {
+				return true
+			}
+		}
+	}
+	if property, found := result.Properties["email"]; found {
+		return strings.EqualFold(property.Value.String(), email)
+	}
+	return false
+}
+
+// SetProperties adds the properties of your own struct to the contact's AdditionalProperties, using its `hubspot` struct
+// tags as described in MarshalProperties. This lets you keep custom properties in typed fields:
+//
+//	type Customer struct {
+//		PlanLevel  string    `hubspot:"plan_level"`
+//		DogCount   int       `hubspot:"dog_count,omitempty"`
+//		RenewsOn   time.Time `hubspot:"renews_on,omitempty"`
+//		Interests  []string  `hubspot:"interests"`
+//	}
+//	err := contact.SetProperties(customer)
+func (contact *Contact) SetProperties(v interface{}) error {
+	props, err := marshalPropertyList(v)
+	if err != nil {
+		return err
+	}
+	if contact.AdditionalProperties == nil {
+		contact.AdditionalProperties = &[]ContactProperty{}
+	}
+	for _, p := range props {
+		*contact.AdditionalProperties = append(*contact.AdditionalProperties, ContactProperty{
+			Property: p.Name,
+			Value:    p.Value,
+		})
+	}
+	return nil
+}
+
+// populateContactFields fills in the contact from Hubspot's representation of it
+func (contact *Contact) populateContactFields(result contactResponse) {
+	contact.VID = result.VID
+	// the contact only has string fields, which can't fail to convert
+	UnmarshalProperties(result.propertyValues(), contact)
+
+	contact.Properties = make(map[string]ContactPropertyValue, len(result.Properties))
+	for name, property := range result.Properties {
+		value := ContactPropertyValue{
+			Value: property.Value.String(),
+		}
+		for _, version := range property.Versions {
+			value.Versions = append(value.Versions, ContactPropertyVersion{
+				Value:       version.Value.String(),
+				SourceType:  version.SourceType,
+				SourceID:    version.SourceID.String(),
+				SourceLabel: version.SourceLabel,
+				Timestamp:   version.Timestamp.Time,
+				Selected:    version.Selected,
 			})
 		}
+		contact.Properties[name] = value
+	}
+
+	contact.IdentityProfiles = nil
+	for _, profile := range result.IdentityProfiles {
+		identityProfile := ContactIdentityProfile{
+			VID:            profile.VID,
+			SavedAt:        profile.SavedAtTimestamp.Time,
+			DeletedChanged: profile.DeletedChangedTimestamp.Time,
+		}
+		for _, identity := range profile.Identities {
+			identityProfile.Identities = append(identityProfile.Identities, ContactIdentity{
+				Type:      identity.Type,
+				Value:     identity.Value,
+				Timestamp: identity.Timestamp.Time,
+				IsPrimary: identity.IsPrimary,
+			})
+		}
+		contact.IdentityProfiles = append(contact.IdentityProfiles, identityProfile)
+	}
+
+	contact.FormSubmissions = nil
+	for _, submission := range result.FormSubmissions {
+		contact.FormSubmissions = append(contact.FormSubmissions, ContactFormSubmission{
+			ConversionID: submission.ConversionID,
+			Timestamp:    submission.Timestamp.Time,
+			FormID:       submission.FormID,
+			PortalID:     submission.PortalID,
+			PageURL:      submission.PageURL,
+			PageTitle:    submission.PageTitle,
+			Title:        submission.Title,
+			FormType:     submission.FormType,
+		})
 	}
+
+	contact.ListMemberships = nil
+	for _, membership := range result.ListMemberships {
+		contact.ListMemberships = append(contact.ListMemberships, ContactListMembership{
+			StaticListID:   membership.StaticListID,
+			InternalListID: membership.InternalListID,
+			Timestamp:      membership.Timestamp.Time,
+			IsMember:       membership.IsMember,
+		})
+	}
+}
+
+// propertyValues returns the current value of each of the contact's properties
+func (result contactResponse) propertyValues() map[string]string {
+	values := make(map[string]string, len(result.Properties))
+	for name, property := range result.Properties {
+		values[name] = property.Value.String()
+	}
+	return values
+}
+
+// ClearProperty adds the properties to ClearProperties, so they are blanked out the next time the contact is saved
+func (contact *Contact) ClearProperty(names ...string) {
+	contact.ClearProperties = append(contact.ClearProperties, names...)
+}
+
+// convertContactToProperties builds the properties to send for the contact. Only the fields that are set, the
+// additional properties and the properties to clear are included, so every save is a partial update.
+func (contact *Contact) convertContactToProperties() []map[string]string {
+	// the contact only has string fields, which can't fail to convert
+	fields, _ := marshalPropertyList(contact)
 	// now we need to merge the additional properties
 	if contact.AdditionalProperties != nil {
 		for _, p := range *contact.AdditionalProperties {
-			props = append(props, map[string]string{
-				"property": p.Property,
-				"value":    p.Value,
-			})
+			fields = append(fields, property{Name: p.Property, Value: p.Value})
+		}
+	}
+	fields = clearProperties(fields, contact.ClearProperties)
+
+	props := make([]map[string]string, len(fields))
+	for i, p := range fields {
+		props[i] = map[string]string{
+			"property": p.Name,
+			"value":    p.Value,
 		}
 	}
 	return props
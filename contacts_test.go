@@ -1,9 +1,13 @@
 package hubspot
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
@@ -202,3 +206,231 @@ func TestConversion(t *testing.T) {
 	assert.False(t, cityFound)
 	assert.True(t, userIDFound)
 }
+
+func TestContactDecoding(t *testing.T) {
+	body := ""
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+	defer server.Close()
+
+	// nulls, numbers and booleans used to panic
+	body = `{"vid": 42, "properties": {
+		"firstname": {"value": "Test"},
+		"lastname": {"value": null},
+		"zip": {"value": 3801},
+		"company": {"value": true}
+	}}`
+	contact, err := client.GetContactByEmail(context.Background(), "test@test.com")
+	require.Nil(t, err)
+	assert.Equal(t, int64(42), contact.VID)
+	assert.Equal(t, "Test", contact.FirstName)
+	assert.Equal(t, "", contact.LastName)
+	assert.Equal(t, "3801", contact.Zip)
+	assert.Equal(t, "true", contact.Company)
+
+	// a payload that isn't what we expect is an error, not a crash
+	body = `{"vid": "not a number", "properties": []}`
+	_, err = client.GetContactByEmail(context.Background(), "test@test.com")
+	require.NotNil(t, err)
+	apiErr, cOK := err.(APIError)
+	require.True(t, cOK)
+	assert.Equal(t, CodeResponseCouldNotBeDecoded, apiErr.SystemCode)
+
+	body = `{"vid": 1, "properties": {"firstname": {"value": {"nested": "object"}}}}`
+	_, err = client.GetContactByEmail(context.Background(), "test@test.com")
+	require.NotNil(t, err)
+	assert.Equal(t, CodeResponseCouldNotBeDecoded, err.(APIError).SystemCode)
+
+	body = `{"vid": 43, "isNew": true}`
+	input := Contact{Email: "test@test.com"}
+	err = client.CreateOrUpdateContact(context.Background(), &input)
+	require.Nil(t, err)
+	assert.Equal(t, int64(43), input.VID)
+}
+
+func TestContactLookups(t *testing.T) {
+	var queries []url.Values
+	paths := []string{}
+	escapedPath := ""
+	body := ""
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		escapedPath = r.URL.EscapedPath()
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+	defer server.Close()
+
+	_, err := client.GetContactByVID(context.Background(), 0, nil)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeContactVIDZero, err.(APIError).SystemCode)
+	_, err = client.GetContactByUTK(context.Background(), "", nil)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeContactNoUTK, err.(APIError).SystemCode)
+	_, err = client.GetContactsByVIDs(context.Background(), []int64{}, nil)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeContactBatchMissingData, err.(APIError).SystemCode)
+	assert.Empty(t, paths)
+
+	body = `{"vid": 42, "properties": {"firstname": {"value": "Test"}}}`
+	contact, err := client.GetContactByVID(context.Background(), 42, &ContactQueryOptions{
+		Properties:          []string{"firstname", "lastname"},
+		PropertyMode:        ContactPropertyModeValueAndHistory,
+		FormSubmissionMode:  ContactFormSubmissionModeNone,
+		ShowListMemberships: true,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, int64(42), contact.VID)
+	assert.Equal(t, "Test", contact.FirstName)
+	assert.Equal(t, "/contacts/v1/contact/vid/42/profile", paths[0])
+	assert.Equal(t, []string{"firstname", "lastname"}, queries[0]["property"])
+	assert.Equal(t, "value_and_history", queries[0].Get("propertyMode"))
+	assert.Equal(t, "none", queries[0].Get("formSubmissionMode"))
+	assert.Equal(t, "true", queries[0].Get("showListMemberships"))
+	assert.Equal(t, "test-key", queries[0].Get("hapikey"))
+
+	_, err = client.GetContactByUTK(context.Background(), "abc123", nil)
+	require.Nil(t, err)
+	assert.Equal(t, "/contacts/v1/contact/utk/abc123/profile", paths[1])
+
+	// the emails batch is keyed by VID, so it is matched back to the emails we asked for, including secondary ones
+	body = `{
+		"1": {"vid": 1, "properties": {"email": {"value": "one@test.com"}}, "identity-profiles": [{"vid": 1, "identities": [
+			{"type": "EMAIL", "value": "one@test.com"}, {"type": "EMAIL", "value": "Other@Test.com"}]}]},
+		"2": {"vid": 2, "properties": {"email": {"value": "two@test.com"}}}
+	}`
+	byEmail, err := client.GetContactsByEmails(context.Background(), []string{"one@test.com", "other@test.com", "two@test.com", "missing@test.com"}, nil)
+	require.Nil(t, err)
+	assert.Len(t, byEmail, 3)
+	assert.Equal(t, int64(1), byEmail["one@test.com"].VID)
+	assert.Equal(t, int64(1), byEmail["other@test.com"].VID)
+	assert.Equal(t, int64(2), byEmail["two@test.com"].VID)
+	assert.Equal(t, "/contacts/v1/contact/emails/batch/", paths[2])
+	assert.Len(t, queries[2]["email"], 4)
+
+	body = `{"abc": {"vid": 7, "properties": {}}}`
+	byUTK, err := client.GetContactsByUTKs(context.Background(), []string{"abc", "def"}, nil)
+	require.Nil(t, err)
+	assert.Equal(t, int64(7), byUTK["abc"].VID)
+	assert.Equal(t, []string{"abc", "def"}, queries[3]["utk"])
+
+	// more than Hubspot allows in one request is split up
+	body = `{"5": {"vid": 5, "properties": {}}}`
+	vids := []int64{}
+	for i := int64(1); i <= 250; i++ {
+		vids = append(vids, i)
+	}
+	paths = []string{}
+	queries = nil
+	byVID, err := client.GetContactsByVIDs(context.Background(), vids, &ContactQueryOptions{Properties: []string{"email"}})
+	require.Nil(t, err)
+	assert.Equal(t, int64(5), byVID[5].VID)
+	require.Len(t, queries, 3)
+	assert.Len(t, queries[0]["vid"], 100)
+	assert.Len(t, queries[1]["vid"], 100)
+	assert.Len(t, queries[2]["vid"], 50)
+	assert.Equal(t, "201", queries[2]["vid"][0])
+	assert.Equal(t, []string{"email"}, queries[2]["property"])
+
+	// the email and user token are escaped, so characters that are legal in them can't change the path
+	_, err = client.GetContactByUTK(context.Background(), "abc/123?x", nil)
+	require.Nil(t, err)
+	assert.Equal(t, "/contacts/v1/contact/utk/abc%2F123%3Fx/profile", escapedPath)
+	_, err = client.GetContactByEmail(context.Background(), "first#last/x@test.com")
+	require.Nil(t, err)
+	assert.Equal(t, "/contacts/v1/contact/email/first%23last%2Fx@test.com/profile", escapedPath)
+}
+
+func TestContactUpdateAndMerge(t *testing.T) {
+	status := http.StatusNoContent
+	var path string
+	var sent map[string]interface{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		sent = map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(status)
+	})
+	defer server.Close()
+
+	err := client.UpdateContactByVID(context.Background(), &Contact{FirstName: "Test"})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeContactVIDZero, err.(APIError).SystemCode)
+	err = client.UpdateContactByEmail(context.Background(), "", &Contact{FirstName: "Test"})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeContactNoEmail, err.(APIError).SystemCode)
+	err = client.MergeContacts(context.Background(), 1, 1)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeContactMergeSameVID, err.(APIError).SystemCode)
+	err = client.MergeContacts(context.Background(), 0, 1)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeContactVIDZero, err.(APIError).SystemCode)
+	assert.Equal(t, "", path)
+
+	// an update by VID can change the email address
+	err = client.UpdateContactByVID(context.Background(), &Contact{VID: 42, Email: "new@test.com"})
+	require.Nil(t, err)
+	assert.Equal(t, "/contacts/v1/contact/vid/42/profile", path)
+	assert.Equal(t, []interface{}{map[string]interface{}{"property": "email", "value": "new@test.com"}}, sent["properties"])
+
+	err = client.UpdateContactByEmail(context.Background(), "old@test.com", &Contact{Email: "new@test.com"})
+	require.Nil(t, err)
+	assert.Equal(t, "/contacts/v1/contact/email/old@test.com/profile", path)
+	// characters that are legal in an email can't change the path
+	err = client.UpdateContactByEmail(context.Background(), "first#last/x@test.com", &Contact{FirstName: "Test"})
+	require.Nil(t, err)
+	assert.Equal(t, "/contacts/v1/contact/email/first%23last%2Fx@test.com/profile", path)
+
+	err = client.MergeContacts(context.Background(), 42, 43)
+	require.Nil(t, err)
+	assert.Equal(t, "/contacts/v1/contact/merge-vids/42/", path)
+	assert.Equal(t, float64(43), sent["vidToMerge"])
+
+	status = http.StatusNotFound
+	err = client.UpdateContactByEmail(context.Background(), "missing@test.com", &Contact{FirstName: "Test"})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeContactNotFound, err.(APIError).SystemCode)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	status = http.StatusBadRequest
+	err = client.UpdateContactByVID(context.Background(), &Contact{VID: 42, FirstName: "Test"})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeContactCouldNotBeUpdated, err.(APIError).SystemCode)
+	err = client.MergeContacts(context.Background(), 42, 43)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeContactCouldNotBeMerged, err.(APIError).SystemCode)
+}
+
+func TestContactClearProperties(t *testing.T) {
+	var sent map[string][]map[string]string
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		sent = map[string][]map[string]string{}
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer server.Close()
+
+	// only what is set is sent, and cleared properties are sent blank
+	contact := Contact{VID: 42, FirstName: "Test", Company: "Old Company"}
+	contact.ClearProperty("phone", "company", "phone")
+	blank := ""
+	plan := "gold"
+	require.Nil(t, contact.SetProperties(struct {
+		Plan      *string `hubspot:"plan_level"`
+		Referrer  *string `hubspot:"referrer"`
+		Untouched *string `hubspot:"untouched"`
+	}{Plan: &plan, Referrer: &blank}))
+
+	err := client.UpdateContactByVID(context.Background(), &contact)
+	require.Nil(t, err)
+	assert.Equal(t, []map[string]string{
+		{"property": "firstname", "value": "Test"},
+		{"property": "plan_level", "value": "gold"},
+		{"property": "referrer", "value": ""},
+		{"property": "phone", "value": ""},
+		{"property": "company", "value": ""},
+	}, sent["properties"])
+}
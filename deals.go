@@ -0,0 +1,438 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// dealPageSize is the most deals Hubspot will return in one page of a listing
+const dealPageSize = 250
+
+// dealRecentPageSize is the most deals Hubspot will return in one page of the recently modified deals
+const dealRecentPageSize = 100
+
+// Deal is a sale in progress, tracked through the stages of a pipeline. ID is 0 until the deal has been created.
+//
+// DealStage and Pipeline hold the internal IDs of the stage and pipeline, not their labels. Amount is a pointer so that
+// an amount of 0 can be sent; it is left out when nil, and CloseDate is left out when it is zero. Use ClearProperty to
+// blank either of them out in Hubspot. The other fields work the same way as they do on a Company.
+type Deal struct {
+	ID          int64     `hubspot:"-"`
+	Name        string    `hubspot:"dealname,omitempty"`
+	Amount      *float64  `hubspot:"amount"`
+	CloseDate   time.Time `hubspot:"closedate,omitempty"`
+	DealStage   string    `hubspot:"dealstage,omitempty"`
+	Pipeline    string    `hubspot:"pipeline,omitempty"`
+	DealType    string    `hubspot:"dealtype,omitempty"`
+	Description string    `hubspot:"description,omitempty"`
+	// AssociatedVIDs and AssociatedCompanyIDs are sent when the deal is created and filled in when it is fetched. To
+	// change them afterwards, use the Add and Remove functions such as AddContactToDeal.
+	AssociatedVIDs       []int64           `hubspot:"-"`
+	AssociatedCompanyIDs []int64           `hubspot:"-"`
+	AdditionalProperties *[]ObjectProperty `hubspot:"-"`
+	ClearProperties      []string          `hubspot:"-"`
+	Properties           ObjectProperties  `hubspot:"-"`
+}
+
+// DealListOptions controls a deal listing
+type DealListOptions struct {
+	// Properties are the properties to return for each deal in ListDeals. The recently modified deals always come with
+	// all of their properties.
+	Properties []string
+	// PropertiesWithHistory also returns the versions of the properties
+	PropertiesWithHistory bool
+	// Count is the number of deals fetched per page, up to 250 for ListDeals and 100 for ListRecentlyModifiedDeals. It
+	// defaults to the most allowed.
+	Count int
+	// Offset resumes a listing from a cursor returned by DealIterator.Offset
+	Offset int64
+	// Since limits ListRecentlyModifiedDeals to the deals modified after it. Hubspot only keeps the last 30 days.
+	Since time.Time
+}
+
+// dealResponse is a deal as returned by Hubspot
+type dealResponse struct {
+	DealID       int64                             `json:"dealId"`
+	PortalID     int64                             `json:"portalId"`
+	IsDeleted    bool                              `json:"isDeleted"`
+	Associations dealAssociations                  `json:"associations"`
+	Properties   map[string]objectPropertyResponse `json:"properties"`
+}
+
+// dealAssociations are the objects associated with a deal
+type dealAssociations struct {
+	AssociatedVIDs       []int64 `json:"associatedVids"`
+	AssociatedCompanyIDs []int64 `json:"associatedCompanyIds"`
+	AssociatedDealIDs    []int64 `json:"associatedDealIds,omitempty"`
+}
+
+// dealListResponse is a single page of deals as returned by Hubspot. The full listing calls the deals "deals" while
+// the recently modified listing calls them "results".
+type dealListResponse struct {
+	Deals   []dealResponse `json:"deals"`
+	Results []dealResponse `json:"results"`
+	HasMore bool           `json:"hasMore"`
+	Offset  int64          `json:"offset"`
+}
+
+// CreateDeal creates a new deal, associated with AssociatedVIDs and AssociatedCompanyIDs. The ID of the deal is filled
+// in once it has been created.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/deals/create_deal
+func (c *Client) CreateDeal(ctx context.Context, deal *Deal) error {
+	props, err := deal.convertDealToProperties()
+	if err != nil {
+		return err
+	}
+	if len(props) == 0 {
+		return APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeDealMissingData,
+			Message:    "you must provide at least one property for a deal, such as its Name or DealStage",
+			Body:       nil,
+		}
+	}
+
+	result := dealResponse{}
+	_, err = c.prepareCall(ctx, EndpointCreateDeal, map[string]string{}, map[string]interface{}{
+		"associations": dealAssociations{
+			AssociatedVIDs:       int64sOrEmpty(deal.AssociatedVIDs),
+			AssociatedCompanyIDs: int64sOrEmpty(deal.AssociatedCompanyIDs),
+		},
+		"properties": props,
+	}, &result)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+			apiErr.SystemCode = CodeDealCouldNotBeCreated
+			return apiErr
+		}
+		return err
+	}
+	return deal.populateDealFields(result)
+}
+
+// CreateDeal calls Client.CreateDeal on the DefaultClient with a background context
+func CreateDeal(deal *Deal) error {
+	return DefaultClient().CreateDeal(context.Background(), deal)
+}
+
+// UpdateDeal updates an existing deal using its ID. Only the properties that are set, and those in ClearProperties, are
+// changed, so moving a deal to another stage only needs the ID and the DealStage. The deal is filled in with what
+// Hubspot returns.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/deals/update_deal
+func (c *Client) UpdateDeal(ctx context.Context, deal *Deal) error {
+	if deal.ID == 0 {
+		return dealIDZeroError("updating")
+	}
+	props, err := deal.convertDealToProperties()
+	if err != nil {
+		return err
+	}
+
+	result := dealResponse{}
+	_, err = c.prepareCall(ctx, EndpointUpdateDeal, map[string]string{
+		":dealID": fmt.Sprintf("%d", deal.ID),
+	}, map[string]interface{}{
+		"properties": props,
+	}, &result)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeDealNotFound
+				return apiErr
+			}
+			apiErr.SystemCode = CodeDealCouldNotBeUpdated
+			return apiErr
+		}
+		return err
+	}
+	return deal.populateDealFields(result)
+}
+
+// UpdateDeal calls Client.UpdateDeal on the DefaultClient with a background context
+func UpdateDeal(deal *Deal) error {
+	return DefaultClient().UpdateDeal(context.Background(), deal)
+}
+
+// GetDealByID gets a single deal by its ID, with all of its properties and associations
+//
+// API Doc: https://developers.hubspot.com/docs/methods/deals/get_deal
+func (c *Client) GetDealByID(ctx context.Context, dealID int64) (Deal, error) {
+	deal := Deal{}
+	if dealID == 0 {
+		return deal, dealIDZeroError("getting")
+	}
+	result := dealResponse{}
+	_, err := c.prepareCall(ctx, EndpointGetDeal, map[string]string{
+		":dealID": fmt.Sprintf("%d", dealID),
+	}, nil, &result)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeDealNotFound
+				return deal, apiErr
+			}
+			apiErr.SystemCode = CodeGeneralError
+			return deal, apiErr
+		}
+		return deal, err
+	}
+	err = deal.populateDealFields(result)
+	return deal, err
+}
+
+// GetDealByID calls Client.GetDealByID on the DefaultClient with a background context
+func GetDealByID(dealID int64) (Deal, error) {
+	return DefaultClient().GetDealByID(context.Background(), dealID)
+}
+
+// DeleteDealByID deletes a single deal by its ID
+//
+// API Doc: https://developers.hubspot.com/docs/methods/deals/delete_deal
+func (c *Client) DeleteDealByID(ctx context.Context, dealID int64) error {
+	if dealID == 0 {
+		return dealIDZeroError("deleting")
+	}
+	_, err := c.prepareCall(ctx, EndpointDeleteDeal, map[string]string{
+		":dealID": fmt.Sprintf("%d", dealID),
+	}, nil, nil)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeDealNotFound
+				return apiErr
+			}
+			apiErr.SystemCode = CodeDealCouldNotBeDeleted
+			return apiErr
+		}
+	}
+	return err
+}
+
+// DeleteDealByID calls Client.DeleteDealByID on the DefaultClient with a background context
+func DeleteDealByID(dealID int64) error {
+	return DefaultClient().DeleteDealByID(context.Background(), dealID)
+}
+
+// DealIterator walks through a listing of deals, fetching pages from Hubspot as they are needed. It is used the same
+// way as a ContactIterator.
+type DealIterator struct {
+	pager
+	page []Deal
+}
+
+// Next advances to the next deal. It returns false when there are no more deals or an error occurred, which is then
+// available from Err.
+func (it *DealIterator) Next() bool {
+	return it.next()
+}
+
+// Deal returns the current deal. It is only valid after a call to Next returns true. A deal with an amount or close
+// date that does not convert is still returned, with that field unset and the value kept in Properties.
+func (it *DealIterator) Deal() Deal {
+	if i := it.current(); i >= 0 {
+		return it.page[i]
+	}
+	return Deal{}
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *DealIterator) Err() error {
+	return it.err
+}
+
+// Offset returns a cursor to resume the listing from with DealListOptions.Offset. See pager.cursor for where it
+// points.
+func (it *DealIterator) Offset() int64 {
+	return it.offset()
+}
+
+// ListDeals walks every deal in the portal, along with its associations
+//
+// API Doc: https://developers.hubspot.com/docs/methods/deals/get-all-deals
+func (c *Client) ListDeals(ctx context.Context, opts *DealListOptions) *DealIterator {
+	if opts == nil {
+		opts = &DealListOptions{}
+	}
+	propertyParam := "properties"
+	if opts.PropertiesWithHistory {
+		propertyParam = "propertiesWithHistory"
+	}
+	return c.listDeals(ctx, EndpointListDeals, opts.Offset, func(offset int64) url.Values {
+		query := url.Values{}
+		query.Set("limit", fmt.Sprintf("%d", pageCount(opts.Count, dealPageSize)))
+		query.Set("includeAssociations", "true")
+		if offset != 0 {
+			query.Set("offset", fmt.Sprintf("%d", offset))
+		}
+		for _, property := range opts.Properties {
+			query.Add(propertyParam, property)
+		}
+		return query
+	})
+}
+
+// ListDeals calls Client.ListDeals on the DefaultClient with a background context
+func ListDeals(opts *DealListOptions) *DealIterator {
+	return DefaultClient().ListDeals(context.Background(), opts)
+}
+
+// ListRecentlyModifiedDeals walks the deals modified in the last 30 days, or since opts.Since, newest first. Hubspot
+// stops the listing after 10,000 deals.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/deals/get_deals_modified
+func (c *Client) ListRecentlyModifiedDeals(ctx context.Context, opts *DealListOptions) *DealIterator {
+	if opts == nil {
+		opts = &DealListOptions{}
+	}
+	return c.listDeals(ctx, EndpointListRecentlyModifiedDeals, opts.Offset, func(offset int64) url.Values {
+		query := url.Values{}
+		query.Set("count", fmt.Sprintf("%d", pageCount(opts.Count, dealRecentPageSize)))
+		if offset != 0 {
+			query.Set("offset", fmt.Sprintf("%d", offset))
+		}
+		if !opts.Since.IsZero() {
+			query.Set("since", fmt.Sprintf("%d", toMilliseconds(opts.Since)))
+		}
+		if opts.PropertiesWithHistory {
+			query.Set("includePropertyVersions", "true")
+		}
+		return query
+	})
+}
+
+// ListRecentlyModifiedDeals calls Client.ListRecentlyModifiedDeals on the DefaultClient with a background context
+func ListRecentlyModifiedDeals(opts *DealListOptions) *DealIterator {
+	return DefaultClient().ListRecentlyModifiedDeals(context.Background(), opts)
+}
+
+// listDeals builds an iterator over one of the deal listings, using the query built for each page
+func (c *Client) listDeals(ctx context.Context, endpoint string, offset int64, query func(offset int64) url.Values) *DealIterator {
+	it := &DealIterator{}
+	it.pager = newPager(ctx, offset, func(ctx context.Context, offset int64) (int, int64, bool, error) {
+		result := dealListResponse{}
+		_, err := c.prepareCall(ctx, endpoint, map[string]string{}, query(offset), &result)
+		if err != nil {
+			if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+				apiErr.SystemCode = CodeDealCouldNotBeListed
+				return 0, offset, false, apiErr
+			}
+			return 0, offset, false, err
+		}
+		deals := result.Deals
+		if len(deals) == 0 {
+			deals = result.Results
+		}
+		it.page = make([]Deal, len(deals))
+		for i := range deals {
+			// a value that does not convert is left out of its field but kept in Properties, as GetDealByID does,
+			// rather than ending the listing
+			it.page[i].populateDealFields(deals[i])
+		}
+		return len(it.page), result.Offset, result.HasMore, nil
+	})
+	return it
+}
+
+// AddContactToDeal associates the contact with the deal. A deal can have many contacts.
+func (c *Client) AddContactToDeal(ctx context.Context, dealID, vid int64) error {
+	return c.Associate(ctx, dealID, vid, AssociationDealToContact)
+}
+
+// AddContactToDeal calls Client.AddContactToDeal on the DefaultClient with a background context
+func AddContactToDeal(dealID, vid int64) error {
+	return DefaultClient().AddContactToDeal(context.Background(), dealID, vid)
+}
+
+// RemoveContactFromDeal removes the association between the contact and the deal
+func (c *Client) RemoveContactFromDeal(ctx context.Context, dealID, vid int64) error {
+	return c.RemoveAssociation(ctx, dealID, vid, AssociationDealToContact)
+}
+
+// RemoveContactFromDeal calls Client.RemoveContactFromDeal on the DefaultClient with a background context
+func RemoveContactFromDeal(dealID, vid int64) error {
+	return DefaultClient().RemoveContactFromDeal(context.Background(), dealID, vid)
+}
+
+// AddCompanyToDeal associates the company with the deal
+func (c *Client) AddCompanyToDeal(ctx context.Context, dealID, companyID int64) error {
+	return c.Associate(ctx, dealID, companyID, AssociationDealToCompany)
+}
+
+// AddCompanyToDeal calls Client.AddCompanyToDeal on the DefaultClient with a background context
+func AddCompanyToDeal(dealID, companyID int64) error {
+	return DefaultClient().AddCompanyToDeal(context.Background(), dealID, companyID)
+}
+
+// RemoveCompanyFromDeal removes the association between the company and the deal
+func (c *Client) RemoveCompanyFromDeal(ctx context.Context, dealID, companyID int64) error {
+	return c.RemoveAssociation(ctx, dealID, companyID, AssociationDealToCompany)
+}
+
+// RemoveCompanyFromDeal calls Client.RemoveCompanyFromDeal on the DefaultClient with a background context
+func RemoveCompanyFromDeal(dealID, companyID int64) error {
+	return DefaultClient().RemoveCompanyFromDeal(context.Background(), dealID, companyID)
+}
+
+// SetProperties adds the properties of your own struct to the deal's AdditionalProperties, using its `hubspot` struct
+// tags as described in MarshalProperties
+func (deal *Deal) SetProperties(v interface{}) error {
+	return appendObjectProperties(&deal.AdditionalProperties, v)
+}
+
+// ClearProperty adds the properties to ClearProperties, so they are blanked out the next time the deal is saved
+func (deal *Deal) ClearProperty(names ...string) {
+	deal.ClearProperties = append(deal.ClearProperties, names...)
+}
+
+// populateDealFields fills in the deal from Hubspot's representation of it. The error is from converting the amount or
+// close date. Each property is converted on its own, so the field that did not convert is left unset but the rest of
+// the deal is still filled in.
+func (deal *Deal) populateDealFields(result dealResponse) error {
+	deal.ID = result.DealID
+	deal.AssociatedVIDs = result.Associations.AssociatedVIDs
+	deal.AssociatedCompanyIDs = result.Associations.AssociatedCompanyIDs
+	deal.Properties = newObjectProperties(result.Properties)
+
+	values := deal.Properties.values()
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var convertErr error
+	for _, name := range names {
+		if err := UnmarshalProperties(map[string]string{name: values[name]}, deal); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}
+	return convertErr
+}
+
+// convertDealToProperties builds the properties to send for the deal
+func (deal *Deal) convertDealToProperties() ([]ObjectProperty, error) {
+	return objectPropertyList(deal, deal.AdditionalProperties, deal.ClearProperties)
+}
+
+// dealIDZeroError is returned when a deal is needed but its ID is missing
+func dealIDZeroError(action string) APIError {
+	return APIError{
+		HTTPCode:   http.StatusBadRequest,
+		SystemCode: CodeDealIDZero,
+		Message:    fmt.Sprintf("the ID for the deal cannot be 0 when %s it", action),
+		Body:       nil,
+	}
+}
+
+// int64sOrEmpty makes sure a list of IDs is sent as an empty array rather than null
+func int64sOrEmpty(ids []int64) []int64 {
+	if ids == nil {
+		return []int64{}
+	}
+	return ids
+}
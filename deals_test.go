@@ -0,0 +1,198 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDealResponse = `{
+	"portalId": 62515,
+	"dealId": 151088,
+	"isDeleted": false,
+	"associations": {"associatedVids": [27], "associatedCompanyIds": [10444744], "associatedDealIds": []},
+	"properties": {
+		"dealname": {"value": "Annual subscription", "timestamp": 1577836800000, "source": "API", "sourceId": null},
+		"amount": {"value": "1200.50", "timestamp": 1577836800000, "source": "API"},
+		"closedate": {"value": "1580515200000", "timestamp": 1577836800000, "source": "API"},
+		"dealstage": {"value": "appointmentscheduled", "timestamp": 1577836800000, "source": "API"},
+		"pipeline": {"value": "default", "timestamp": 1577836800000, "source": "API"}
+	}
+}`
+
+func TestDealWrites(t *testing.T) {
+	status := http.StatusOK
+	var method, path string
+	var sent map[string]interface{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		sent = nil
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, testDealResponse)
+	})
+	defer server.Close()
+
+	err := client.CreateDeal(context.Background(), &Deal{})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeDealMissingData, err.(APIError).SystemCode)
+	err = client.UpdateDeal(context.Background(), &Deal{DealStage: "closedwon"})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeDealIDZero, err.(APIError).SystemCode)
+	assert.Equal(t, "", path)
+
+	closeDate := time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+	amount := 1200.5
+	deal := Deal{
+		Name:                 "Annual subscription",
+		Amount:               &amount,
+		CloseDate:            closeDate,
+		DealStage:            "appointmentscheduled",
+		Pipeline:             "default",
+		AssociatedCompanyIDs: []int64{10444744},
+	}
+	require.Nil(t, client.CreateDeal(context.Background(), &deal))
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/deals/v1/deal", path)
+	assert.Equal(t, map[string]interface{}{
+		"associatedVids":       []interface{}{},
+		"associatedCompanyIds": []interface{}{float64(10444744)},
+	}, sent["associations"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "dealname", "value": "Annual subscription"},
+		map[string]interface{}{"name": "amount", "value": "1200.5"},
+		map[string]interface{}{"name": "closedate", "value": "1580515200000"},
+		map[string]interface{}{"name": "dealstage", "value": "appointmentscheduled"},
+		map[string]interface{}{"name": "pipeline", "value": "default"},
+	}, sent["properties"])
+	assert.Equal(t, int64(151088), deal.ID)
+	require.NotNil(t, deal.Amount)
+	assert.Equal(t, 1200.50, *deal.Amount)
+	assert.True(t, closeDate.Equal(deal.CloseDate))
+	assert.Equal(t, []int64{27}, deal.AssociatedVIDs)
+
+	update := Deal{ID: 151088, DealStage: "closedwon"}
+	update.ClearProperty("amount")
+	require.Nil(t, client.UpdateDeal(context.Background(), &update))
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/deals/v1/deal/151088", path)
+	assert.Equal(t, map[string]interface{}{"properties": []interface{}{
+		map[string]interface{}{"name": "dealstage", "value": "closedwon"},
+		map[string]interface{}{"name": "amount", "value": ""},
+	}}, sent)
+	assert.Equal(t, "Annual subscription", update.Name)
+
+	// a deal can be marked as worth nothing, which is different from leaving the amount alone
+	zero := 0.0
+	require.Nil(t, client.UpdateDeal(context.Background(), &Deal{ID: 151088, Amount: &zero}))
+	assert.Equal(t, map[string]interface{}{"properties": []interface{}{
+		map[string]interface{}{"name": "amount", "value": "0"},
+	}}, sent)
+
+	status = http.StatusNotFound
+	err = client.UpdateDeal(context.Background(), &update)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeDealNotFound, err.(APIError).SystemCode)
+	err = client.DeleteDealByID(context.Background(), 151088)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeDealNotFound, err.(APIError).SystemCode)
+	assert.Equal(t, http.MethodDelete, method)
+
+	status = http.StatusBadRequest
+	err = client.CreateDeal(context.Background(), &deal)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeDealCouldNotBeCreated, err.(APIError).SystemCode)
+}
+
+func TestDealLookups(t *testing.T) {
+	queries := []url.Values{}
+	var path string
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/deals/v1/deal/151088":
+			fmt.Fprint(w, testDealResponse)
+		case "/deals/v1/deal/2":
+			fmt.Fprint(w, `{"dealId": 2, "properties": {"amount": {"value": "lots"}, "dealstage": {"value": "closedwon"}}}`)
+		case "/deals/v1/deal/paged":
+			if r.URL.Query().Get("offset") == "" {
+				fmt.Fprintf(w, `{"deals": [%s, {"dealId": 2, "properties": {"amount": {"value": "lots"}, "dealstage": {"value": "closedwon"}}}], "hasMore": true, "offset": 2}`, testDealResponse)
+				return
+			}
+			fmt.Fprint(w, `{"deals": [{"dealId": 3}], "hasMore": false, "offset": 3}`)
+		case "/deals/v1/deal/recent/modified":
+			fmt.Fprintf(w, `{"results": [%s], "hasMore": false, "offset": 1, "total": 1}`, testDealResponse)
+		case "/crm-associations/v1/associations":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	deal, err := client.GetDealByID(context.Background(), 151088)
+	require.Nil(t, err)
+	assert.Equal(t, "default", deal.Pipeline)
+	assert.Equal(t, []int64{10444744}, deal.AssociatedCompanyIDs)
+	_, err = client.GetDealByID(context.Background(), 1)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeDealNotFound, err.(APIError).SystemCode)
+	// an amount that isn't a number is reported, but the rest of the deal is still there
+	deal, err = client.GetDealByID(context.Background(), 2)
+	require.NotNil(t, err)
+	assert.Equal(t, CodePropertyCouldNotBeConverted, err.(APIError).SystemCode)
+	assert.Equal(t, int64(2), deal.ID)
+	assert.Equal(t, "lots", deal.Properties.Get("amount"))
+	assert.Nil(t, deal.Amount)
+	assert.Equal(t, "closedwon", deal.DealStage)
+
+	queries = []url.Values{}
+	it := client.ListDeals(context.Background(), &DealListOptions{Properties: []string{"dealname"}, Count: 2})
+	ids := []int64{}
+	for it.Next() {
+		ids = append(ids, it.Deal().ID)
+		if it.Deal().ID == 2 {
+			// the amount that does not convert is kept as it is, the same as from GetDealByID, and the listing goes on
+			assert.Equal(t, "lots", it.Deal().Properties.Get("amount"))
+			assert.Nil(t, it.Deal().Amount)
+			assert.Equal(t, "closedwon", it.Deal().DealStage)
+		}
+	}
+	require.Nil(t, it.Err())
+	assert.Equal(t, []int64{151088, 2, 3}, ids)
+	assert.Equal(t, int64(3), it.Offset())
+	assert.Equal(t, "2", queries[0].Get("limit"))
+	assert.Equal(t, "true", queries[0].Get("includeAssociations"))
+	assert.Equal(t, []string{"dealname"}, queries[0]["properties"])
+	assert.Equal(t, "2", queries[1].Get("offset"))
+
+	queries = []url.Values{}
+	since := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	it = client.ListRecentlyModifiedDeals(context.Background(), &DealListOptions{Since: since, PropertiesWithHistory: true})
+	require.True(t, it.Next())
+	assert.Equal(t, "Annual subscription", it.Deal().Name)
+	assert.False(t, it.Next())
+	require.Nil(t, it.Err())
+	assert.Equal(t, "100", queries[0].Get("count"))
+	assert.Equal(t, "1577836800000", queries[0].Get("since"))
+	assert.Equal(t, "true", queries[0].Get("includePropertyVersions"))
+
+	require.Nil(t, client.AddContactToDeal(context.Background(), 151088, 27))
+	require.Nil(t, client.AddCompanyToDeal(context.Background(), 151088, 10444744))
+	assert.Equal(t, "/crm-associations/v1/associations", path)
+	err = client.RemoveContactFromDeal(context.Background(), 151088, 27)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeAssociationCouldNotBeRemoved, err.(APIError).SystemCode)
+	assert.Equal(t, "/crm-associations/v1/associations/delete", path)
+}
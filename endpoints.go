@@ -5,13 +5,87 @@ import "net/http"
 // Endpoints represent specific calls to the Hubspot API and some meta data about them. These should largely be ignored
 // by the consumer of the API
 const (
-	EndpointCreateContact = "endpointCreateContact"
-	EndpointGetContact    = "endpointGetContact"
-	EndpointDeleteContact = "endpointDeleteContact"
+	EndpointCreateContact        = "endpointCreateContact"
+	EndpointGetContact           = "endpointGetContact"
+	EndpointGetContactByVID      = "endpointGetContactByVID"
+	EndpointGetContactByUTK      = "endpointGetContactByUTK"
+	EndpointGetContactsByEmails  = "endpointGetContactsByEmails"
+	EndpointGetContactsByVIDs    = "endpointGetContactsByVIDs"
+	EndpointGetContactsByUTKs    = "endpointGetContactsByUTKs"
+	EndpointUpdateContactByVID   = "endpointUpdateContactByVID"
+	EndpointUpdateContactByEmail = "endpointUpdateContactByEmail"
+	EndpointMergeContacts        = "endpointMergeContacts"
+	EndpointDeleteContact        = "endpointDeleteContact"
+
+	EndpointBatchCreateOrUpdateContacts = "endpointBatchCreateOrUpdateContacts"
+
+	EndpointListContacts                = "endpointListContacts"
+	EndpointListRecentlyUpdatedContacts = "endpointListRecentlyUpdatedContacts"
+	EndpointListRecentlyCreatedContacts = "endpointListRecentlyCreatedContacts"
+	EndpointSearchContacts              = "endpointSearchContacts"
+
+	EndpointCreateCompany            = "endpointCreateCompany"
+	EndpointUpdateCompany            = "endpointUpdateCompany"
+	EndpointBatchUpdateCompanies     = "endpointBatchUpdateCompanies"
+	EndpointGetCompany               = "endpointGetCompany"
+	EndpointGetCompaniesByDomain     = "endpointGetCompaniesByDomain"
+	EndpointDeleteCompany            = "endpointDeleteCompany"
+	EndpointListCompanies            = "endpointListCompanies"
+	EndpointAddContactToCompany      = "endpointAddContactToCompany"
+	EndpointRemoveContactFromCompany = "endpointRemoveContactFromCompany"
+	EndpointGetCompanyContactVIDs    = "endpointGetCompanyContactVIDs"
+
+	EndpointCreateDeal                = "endpointCreateDeal"
+	EndpointUpdateDeal                = "endpointUpdateDeal"
+	EndpointGetDeal                   = "endpointGetDeal"
+	EndpointDeleteDeal                = "endpointDeleteDeal"
+	EndpointListDeals                 = "endpointListDeals"
+	EndpointListRecentlyModifiedDeals = "endpointListRecentlyModifiedDeals"
+
+	EndpointCreateTicket       = "endpointCreateTicket"
+	EndpointUpdateTicket       = "endpointUpdateTicket"
+	EndpointGetTicket          = "endpointGetTicket"
+	EndpointDeleteTicket       = "endpointDeleteTicket"
+	EndpointBatchCreateTickets = "endpointBatchCreateTickets"
+	EndpointBatchUpdateTickets = "endpointBatchUpdateTickets"
+	EndpointListTickets        = "endpointListTickets"
+
+	EndpointCreateEngagement      = "endpointCreateEngagement"
+	EndpointUpdateEngagement      = "endpointUpdateEngagement"
+	EndpointGetEngagement         = "endpointGetEngagement"
+	EndpointDeleteEngagement      = "endpointDeleteEngagement"
+	EndpointListEngagements       = "endpointListEngagements"
+	EndpointListRecentEngagements = "endpointListRecentEngagements"
+
+	EndpointListPipelines       = "endpointListPipelines"
+	EndpointGetPipeline         = "endpointGetPipeline"
+	EndpointCreatePipeline      = "endpointCreatePipeline"
+	EndpointUpdatePipeline      = "endpointUpdatePipeline"
+	EndpointDeletePipeline      = "endpointDeletePipeline"
+	EndpointListPipelineStages  = "endpointListPipelineStages"
+	EndpointGetPipelineStage    = "endpointGetPipelineStage"
+	EndpointCreatePipelineStage = "endpointCreatePipelineStage"
+	EndpointUpdatePipelineStage = "endpointUpdatePipelineStage"
+	EndpointDeletePipelineStage = "endpointDeletePipelineStage"
+	EndpointListPipelinesV1     = "endpointListPipelinesV1"
+	EndpointCreatePipelineV1    = "endpointCreatePipelineV1"
+	EndpointUpdatePipelineV1    = "endpointUpdatePipelineV1"
+	EndpointDeletePipelineV1    = "endpointDeletePipelineV1"
+
+	EndpointCreateAssociation = "endpointCreateAssociation"
+	EndpointRemoveAssociation = "endpointRemoveAssociation"
+	EndpointGetAssociations   = "endpointGetAssociations"
+
+	EndpointSearchObjects = "endpointSearchObjects"
 
 	EndpointCreateEventType = "endpointCreateEventType"
 	EndpointDeleteEventType = "endpointDeleteEventType"
 	EndpointCreateEvent     = "endpointCreateEvent"
+
+	EndpointCreateOAuthToken        = "endpointCreateOAuthToken"
+	EndpointGetOAuthAccessToken     = "endpointGetOAuthAccessToken"
+	EndpointGetOAuthRefreshToken    = "endpointGetOAuthRefreshToken"
+	EndpointDeleteOAuthRefreshToken = "endpointDeleteOAuthRefreshToken"
 )
 
 type endpoint struct {
@@ -34,11 +108,318 @@ var endpoints = map[string]endpoint{
 		Path:     "/contacts/v1/contact/email/:email/profile",
 		MockGood: nil,
 	},
+	EndpointGetContactByVID: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/contacts/v1/contact/vid/:vid/profile",
+		MockGood: nil,
+	},
+	EndpointGetContactByUTK: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/contacts/v1/contact/utk/:utk/profile",
+		MockGood: nil,
+	},
+	EndpointGetContactsByEmails: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/contacts/v1/contact/emails/batch/",
+		MockGood: nil,
+	},
+	EndpointGetContactsByVIDs: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/contacts/v1/contact/vids/batch/",
+		MockGood: nil,
+	},
+	EndpointGetContactsByUTKs: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/contacts/v1/contact/utks/batch/",
+		MockGood: nil,
+	},
+	EndpointUpdateContactByVID: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/contacts/v1/contact/vid/:vid/profile",
+		MockGood: nil,
+	},
+	EndpointUpdateContactByEmail: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/contacts/v1/contact/email/:email/profile",
+		MockGood: nil,
+	},
+	EndpointMergeContacts: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/contacts/v1/contact/merge-vids/:vid/",
+		MockGood: nil,
+	},
 	EndpointDeleteContact: endpoint{
 		Method:   http.MethodDelete,
 		Path:     "/contacts/v1/contact/vid/:vid",
 		MockGood: nil,
 	},
+	EndpointBatchCreateOrUpdateContacts: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/contacts/v1/contact/batch/",
+		MockGood: nil,
+	},
+	EndpointListContacts: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/contacts/v1/lists/all/contacts/all",
+		MockGood: nil,
+	},
+	EndpointListRecentlyUpdatedContacts: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/contacts/v1/lists/recently_updated/contacts/recent",
+		MockGood: nil,
+	},
+	EndpointListRecentlyCreatedContacts: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/contacts/v1/lists/all/contacts/recent",
+		MockGood: nil,
+	},
+	EndpointSearchContacts: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/contacts/v1/search/query",
+		MockGood: nil,
+	},
+	// Companies
+	EndpointCreateCompany: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/companies/v2/companies",
+		MockGood: nil,
+	},
+	EndpointUpdateCompany: endpoint{
+		Method:   http.MethodPut,
+		Path:     "/companies/v2/companies/:companyID",
+		MockGood: nil,
+	},
+	EndpointBatchUpdateCompanies: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/companies/v1/batch-async/update",
+		MockGood: nil,
+	},
+	EndpointGetCompany: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/companies/v2/companies/:companyID",
+		MockGood: nil,
+	},
+	EndpointGetCompaniesByDomain: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/companies/v2/domains/:domain/companies",
+		MockGood: nil,
+	},
+	EndpointDeleteCompany: endpoint{
+		Method:   http.MethodDelete,
+		Path:     "/companies/v2/companies/:companyID",
+		MockGood: nil,
+	},
+	EndpointListCompanies: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/companies/v2/companies/paged",
+		MockGood: nil,
+	},
+	EndpointAddContactToCompany: endpoint{
+		Method:   http.MethodPut,
+		Path:     "/companies/v2/companies/:companyID/contacts/:vid",
+		MockGood: nil,
+	},
+	EndpointRemoveContactFromCompany: endpoint{
+		Method:   http.MethodDelete,
+		Path:     "/companies/v2/companies/:companyID/contacts/:vid",
+		MockGood: nil,
+	},
+	EndpointGetCompanyContactVIDs: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/companies/v2/companies/:companyID/vids",
+		MockGood: nil,
+	},
+	// Deals
+	EndpointCreateDeal: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/deals/v1/deal",
+		MockGood: nil,
+	},
+	EndpointUpdateDeal: endpoint{
+		Method:   http.MethodPut,
+		Path:     "/deals/v1/deal/:dealID",
+		MockGood: nil,
+	},
+	EndpointGetDeal: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/deals/v1/deal/:dealID",
+		MockGood: nil,
+	},
+	EndpointDeleteDeal: endpoint{
+		Method:   http.MethodDelete,
+		Path:     "/deals/v1/deal/:dealID",
+		MockGood: nil,
+	},
+	EndpointListDeals: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/deals/v1/deal/paged",
+		MockGood: nil,
+	},
+	EndpointListRecentlyModifiedDeals: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/deals/v1/deal/recent/modified",
+		MockGood: nil,
+	},
+	// Tickets
+	EndpointCreateTicket: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/crm-objects/v1/objects/tickets",
+		MockGood: nil,
+	},
+	EndpointUpdateTicket: endpoint{
+		Method:   http.MethodPut,
+		Path:     "/crm-objects/v1/objects/tickets/:ticketID",
+		MockGood: nil,
+	},
+	EndpointGetTicket: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/crm-objects/v1/objects/tickets/:ticketID",
+		MockGood: nil,
+	},
+	EndpointDeleteTicket: endpoint{
+		Method:   http.MethodDelete,
+		Path:     "/crm-objects/v1/objects/tickets/:ticketID",
+		MockGood: nil,
+	},
+	EndpointBatchCreateTickets: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/crm-objects/v1/objects/tickets/batch-create",
+		MockGood: nil,
+	},
+	EndpointBatchUpdateTickets: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/crm-objects/v1/objects/tickets/batch-update",
+		MockGood: nil,
+	},
+	EndpointListTickets: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/crm-objects/v1/objects/tickets/paged",
+		MockGood: nil,
+	},
+	// Engagements
+	EndpointCreateEngagement: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/engagements/v1/engagements",
+		MockGood: nil,
+	},
+	EndpointUpdateEngagement: endpoint{
+		Method:   http.MethodPatch,
+		Path:     "/engagements/v1/engagements/:engagementID",
+		MockGood: nil,
+	},
+	EndpointGetEngagement: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/engagements/v1/engagements/:engagementID",
+		MockGood: nil,
+	},
+	EndpointDeleteEngagement: endpoint{
+		Method:   http.MethodDelete,
+		Path:     "/engagements/v1/engagements/:engagementID",
+		MockGood: nil,
+	},
+	EndpointListEngagements: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/engagements/v1/engagements/paged",
+		MockGood: nil,
+	},
+	EndpointListRecentEngagements: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/engagements/v1/engagements/recent/modified",
+		MockGood: nil,
+	},
+	// Pipelines
+	EndpointListPipelines: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/crm/v3/pipelines/:objectType",
+		MockGood: nil,
+	},
+	EndpointGetPipeline: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/crm/v3/pipelines/:objectType/:pipelineID",
+		MockGood: nil,
+	},
+	EndpointCreatePipeline: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/crm/v3/pipelines/:objectType",
+		MockGood: nil,
+	},
+	EndpointUpdatePipeline: endpoint{
+		Method:   http.MethodPatch,
+		Path:     "/crm/v3/pipelines/:objectType/:pipelineID",
+		MockGood: nil,
+	},
+	EndpointDeletePipeline: endpoint{
+		Method:   http.MethodDelete,
+		Path:     "/crm/v3/pipelines/:objectType/:pipelineID",
+		MockGood: nil,
+	},
+	EndpointListPipelineStages: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/crm/v3/pipelines/:objectType/:pipelineID/stages",
+		MockGood: nil,
+	},
+	EndpointGetPipelineStage: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/crm/v3/pipelines/:objectType/:pipelineID/stages/:stageID",
+		MockGood: nil,
+	},
+	EndpointCreatePipelineStage: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/crm/v3/pipelines/:objectType/:pipelineID/stages",
+		MockGood: nil,
+	},
+	EndpointUpdatePipelineStage: endpoint{
+		Method:   http.MethodPatch,
+		Path:     "/crm/v3/pipelines/:objectType/:pipelineID/stages/:stageID",
+		MockGood: nil,
+	},
+	EndpointDeletePipelineStage: endpoint{
+		Method:   http.MethodDelete,
+		Path:     "/crm/v3/pipelines/:objectType/:pipelineID/stages/:stageID",
+		MockGood: nil,
+	},
+	EndpointListPipelinesV1: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/crm-pipelines/v1/pipelines/:objectType",
+		MockGood: nil,
+	},
+	EndpointCreatePipelineV1: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/crm-pipelines/v1/pipelines/:objectType",
+		MockGood: nil,
+	},
+	EndpointUpdatePipelineV1: endpoint{
+		Method:   http.MethodPut,
+		Path:     "/crm-pipelines/v1/pipelines/:objectType/:pipelineID",
+		MockGood: nil,
+	},
+	EndpointDeletePipelineV1: endpoint{
+		Method:   http.MethodDelete,
+		Path:     "/crm-pipelines/v1/pipelines/:objectType/:pipelineID",
+		MockGood: nil,
+	},
+	// Associations
+	EndpointCreateAssociation: endpoint{
+		Method:   http.MethodPut,
+		Path:     "/crm-associations/v1/associations",
+		MockGood: nil,
+	},
+	EndpointRemoveAssociation: endpoint{
+		Method:   http.MethodPut,
+		Path:     "/crm-associations/v1/associations/delete",
+		MockGood: nil,
+	},
+	EndpointGetAssociations: endpoint{
+		Method:   http.MethodGet,
+		Path:     "/crm-associations/v1/associations/:objectID/HUBSPOT_DEFINED/:definitionID",
+		MockGood: nil,
+	},
+	// CRM search
+	EndpointSearchObjects: endpoint{
+		Method:   http.MethodPost,
+		Path:     "/crm/v3/objects/:objectType/search",
+		MockGood: nil,
+	},
 	// Events
 	EndpointCreateEventType: endpoint{
 		Method:       http.MethodPost,
@@ -66,4 +447,21 @@ var endpoints = map[string]endpoint{
 		MockGoodHTTP: http.StatusNoContent,
 		MockGood:     nil,
 	},
+	// OAuth
+	EndpointCreateOAuthToken: endpoint{
+		Method: http.MethodPost,
+		Path:   "/oauth/v1/token",
+	},
+	EndpointGetOAuthAccessToken: endpoint{
+		Method: http.MethodGet,
+		Path:   "/oauth/v1/access-tokens/:token",
+	},
+	EndpointGetOAuthRefreshToken: endpoint{
+		Method: http.MethodGet,
+		Path:   "/oauth/v1/refresh-tokens/:token",
+	},
+	EndpointDeleteOAuthRefreshToken: endpoint{
+		Method: http.MethodDelete,
+		Path:   "/oauth/v1/refresh-tokens/:token",
+	},
 }
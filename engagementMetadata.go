@@ -0,0 +1,225 @@
+package hubspot
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EngagementType is the kind of activity an engagement records
+type EngagementType string
+
+// The engagement types that have typed metadata
+const (
+	EngagementTypeNote    EngagementType = "NOTE"
+	EngagementTypeEmail   EngagementType = "EMAIL"
+	EngagementTypeTask    EngagementType = "TASK"
+	EngagementTypeMeeting EngagementType = "MEETING"
+	EngagementTypeCall    EngagementType = "CALL"
+)
+
+// EngagementMetadata is the part of an engagement that depends on its type. It is one of NoteMetadata, EmailMetadata,
+// TaskMetadata, MeetingMetadata or CallMetadata.
+type EngagementMetadata interface {
+	engagementType() EngagementType
+}
+
+// NoteMetadata is the metadata of a note
+type NoteMetadata struct {
+	Body string `json:"body,omitempty"`
+}
+
+// EmailAddress is a sender or recipient of an email engagement
+type EmailAddress struct {
+	Email     string `json:"email"`
+	FirstName string `json:"firstName,omitempty"`
+	LastName  string `json:"lastName,omitempty"`
+}
+
+// EmailMetadata is the metadata of an email that was sent or received outside of Hubspot
+type EmailMetadata struct {
+	From    *EmailAddress  `json:"from,omitempty"`
+	To      []EmailAddress `json:"to,omitempty"`
+	CC      []EmailAddress `json:"cc,omitempty"`
+	BCC     []EmailAddress `json:"bcc,omitempty"`
+	Subject string         `json:"subject,omitempty"`
+	HTML    string         `json:"html,omitempty"`
+	Text    string         `json:"text,omitempty"`
+}
+
+// TaskStatus is how far along a task is
+type TaskStatus string
+
+// The statuses a task can have
+const (
+	TaskStatusNotStarted TaskStatus = "NOT_STARTED"
+	TaskStatusInProgress TaskStatus = "IN_PROGRESS"
+	TaskStatusWaiting    TaskStatus = "WAITING"
+	TaskStatusCompleted  TaskStatus = "COMPLETED"
+	TaskStatusDeferred   TaskStatus = "DEFERRED"
+)
+
+// TaskMetadata is the metadata of a task. The Timestamp of the engagement is when the task is due.
+type TaskMetadata struct {
+	Subject string     `json:"subject,omitempty"`
+	Body    string     `json:"body,omitempty"`
+	Status  TaskStatus `json:"status,omitempty"`
+	// ForObjectType is the kind of record the task is for, such as "CONTACT" or "COMPANY"
+	ForObjectType string `json:"forObjectType,omitempty"`
+}
+
+// MeetingMetadata is the metadata of a meeting
+type MeetingMetadata struct {
+	Title                string
+	Body                 string
+	StartTime            time.Time
+	EndTime              time.Time
+	InternalMeetingNotes string
+}
+
+// CallMetadata is the metadata of a phone call
+type CallMetadata struct {
+	ToNumber   string
+	FromNumber string
+	// Status is the state of the call, such as "COMPLETED", "BUSY" or "NO_ANSWER"
+	Status       string
+	Duration     time.Duration
+	RecordingURL string
+	Body         string
+	// Disposition is the ID of the call outcome, as set up in the portal
+	Disposition string
+}
+
+func (NoteMetadata) engagementType() EngagementType    { return EngagementTypeNote }
+func (EmailMetadata) engagementType() EngagementType   { return EngagementTypeEmail }
+func (TaskMetadata) engagementType() EngagementType    { return EngagementTypeTask }
+func (MeetingMetadata) engagementType() EngagementType { return EngagementTypeMeeting }
+func (CallMetadata) engagementType() EngagementType    { return EngagementTypeCall }
+
+// meetingMetadataRequest is the meeting metadata as sent to Hubspot, which wants the times in milliseconds. The times
+// are pointers so that a meeting at the epoch is still sent.
+type meetingMetadataRequest struct {
+	Title                string `json:"title,omitempty"`
+	Body                 string `json:"body,omitempty"`
+	StartTime            *int64 `json:"startTime,omitempty"`
+	EndTime              *int64 `json:"endTime,omitempty"`
+	InternalMeetingNotes string `json:"internalMeetingNotes,omitempty"`
+}
+
+// meetingMetadataResponse is the meeting metadata as returned by Hubspot
+type meetingMetadataResponse struct {
+	Title                string    `json:"title"`
+	Body                 string    `json:"body"`
+	StartTime            timestamp `json:"startTime"`
+	EndTime              timestamp `json:"endTime"`
+	InternalMeetingNotes string    `json:"internalMeetingNotes"`
+}
+
+// MarshalJSON sends the meeting times as milliseconds
+func (m MeetingMetadata) MarshalJSON() ([]byte, error) {
+	return json.Marshal(meetingMetadataRequest{
+		Title:                m.Title,
+		Body:                 m.Body,
+		StartTime:            optionalMilliseconds(m.StartTime),
+		EndTime:              optionalMilliseconds(m.EndTime),
+		InternalMeetingNotes: m.InternalMeetingNotes,
+	})
+}
+
+// UnmarshalJSON reads the meeting times from milliseconds
+func (m *MeetingMetadata) UnmarshalJSON(data []byte) error {
+	response := meetingMetadataResponse{}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return err
+	}
+	*m = MeetingMetadata{
+		Title:                response.Title,
+		Body:                 response.Body,
+		StartTime:            response.StartTime.Time,
+		EndTime:              response.EndTime.Time,
+		InternalMeetingNotes: response.InternalMeetingNotes,
+	}
+	return nil
+}
+
+// callMetadataJSON is the call metadata as Hubspot has it, with the duration in milliseconds
+type callMetadataJSON struct {
+	ToNumber             string `json:"toNumber,omitempty"`
+	FromNumber           string `json:"fromNumber,omitempty"`
+	Status               string `json:"status,omitempty"`
+	DurationMilliseconds int64  `json:"durationMilliseconds,omitempty"`
+	RecordingURL         string `json:"recordingUrl,omitempty"`
+	Body                 string `json:"body,omitempty"`
+	Disposition          string `json:"disposition,omitempty"`
+}
+
+// MarshalJSON sends the call duration as milliseconds
+func (m CallMetadata) MarshalJSON() ([]byte, error) {
+	return json.Marshal(callMetadataJSON{
+		ToNumber:             m.ToNumber,
+		FromNumber:           m.FromNumber,
+		Status:               m.Status,
+		DurationMilliseconds: int64(m.Duration / time.Millisecond),
+		RecordingURL:         m.RecordingURL,
+		Body:                 m.Body,
+		Disposition:          m.Disposition,
+	})
+}
+
+// UnmarshalJSON reads the call duration from milliseconds
+func (m *CallMetadata) UnmarshalJSON(data []byte) error {
+	response := callMetadataJSON{}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return err
+	}
+	*m = CallMetadata{
+		ToNumber:     response.ToNumber,
+		FromNumber:   response.FromNumber,
+		Status:       response.Status,
+		Duration:     time.Duration(response.DurationMilliseconds) * time.Millisecond,
+		RecordingURL: response.RecordingURL,
+		Body:         response.Body,
+		Disposition:  response.Disposition,
+	}
+	return nil
+}
+
+// decodeEngagementMetadata decodes the metadata of an engagement by its type. Types without typed metadata, and
+// engagements without any, have nil metadata.
+func decodeEngagementMetadata(engagementType EngagementType, raw json.RawMessage) (EngagementMetadata, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var err error
+	switch engagementType {
+	case EngagementTypeNote:
+		metadata := NoteMetadata{}
+		err = json.Unmarshal(raw, &metadata)
+		return metadata, err
+	case EngagementTypeEmail:
+		metadata := EmailMetadata{}
+		err = json.Unmarshal(raw, &metadata)
+		return metadata, err
+	case EngagementTypeTask:
+		metadata := TaskMetadata{}
+		err = json.Unmarshal(raw, &metadata)
+		return metadata, err
+	case EngagementTypeMeeting:
+		metadata := MeetingMetadata{}
+		err = json.Unmarshal(raw, &metadata)
+		return metadata, err
+	case EngagementTypeCall:
+		metadata := CallMetadata{}
+		err = json.Unmarshal(raw, &metadata)
+		return metadata, err
+	}
+	return nil, nil
+}
+
+// optionalMilliseconds converts a time for a field that is left out when the time is zero
+func optionalMilliseconds(t time.Time) *int64 {
+	if t.IsZero() {
+		return nil
+	}
+	millis := toMilliseconds(t)
+	return &millis
+}
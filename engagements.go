@@ -0,0 +1,512 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// engagementPageSize is the most engagements Hubspot will return in one page of a listing
+const engagementPageSize = 250
+
+// engagementRecentPageSize is the most engagements Hubspot will return in one page of the recent engagements
+const engagementRecentPageSize = 100
+
+// Engagement is an activity logged on CRM records, such as a note, email, task, meeting or call. ID is 0 until the
+// engagement has been created. Use one of the constructors, such as NewNote, to start a new engagement of a type.
+//
+// The association and attachment IDs are sent when the engagement is created and filled in when it is fetched. To
+// change the associations afterwards, use the Add and Remove functions such as AddContactToEngagement.
+type Engagement struct {
+	ID   int64
+	Type EngagementType
+	// OwnerID is the Hubspot user the engagement belongs to
+	OwnerID int64
+	// Timestamp is when the activity happened, or for a task, when it is due
+	Timestamp  time.Time
+	ContactIDs []int64
+	CompanyIDs []int64
+	DealIDs    []int64
+	TicketIDs  []int64
+	// AttachmentIDs are the IDs of files uploaded with the Files API
+	AttachmentIDs []int64
+	// Metadata is specific to the type, such as a NoteMetadata for a note. It is nil for engagement types the package
+	// does not know, and for engagements in a listing whose metadata could not be decoded.
+	Metadata  EngagementMetadata
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// EngagementListOptions controls an engagement listing
+type EngagementListOptions struct {
+	// Count is the number of engagements fetched per page, up to 250 for ListEngagements and 100 for
+	// ListRecentEngagements. It defaults to the most allowed.
+	Count int
+	// Offset resumes a listing from a cursor returned by EngagementIterator.Offset
+	Offset int64
+	// Since limits ListRecentEngagements to the engagements modified after it. Hubspot only keeps the last 30 days.
+	Since time.Time
+}
+
+// engagementRequest is the body to create or update an engagement
+type engagementRequest struct {
+	Engagement   engagementData          `json:"engagement"`
+	Associations *engagementAssociations `json:"associations,omitempty"`
+	Attachments  []engagementAttachment  `json:"attachments,omitempty"`
+	Metadata     EngagementMetadata      `json:"metadata,omitempty"`
+}
+
+// engagementData is the part of an engagement common to every type. The timestamp is a pointer so that an engagement
+// at the epoch is still sent.
+type engagementData struct {
+	Active    bool           `json:"active,omitempty"`
+	Type      EngagementType `json:"type,omitempty"`
+	OwnerID   int64          `json:"ownerId,omitempty"`
+	Timestamp *int64         `json:"timestamp,omitempty"`
+}
+
+// engagementAssociations are the records an engagement is logged on
+type engagementAssociations struct {
+	ContactIDs []int64 `json:"contactIds"`
+	CompanyIDs []int64 `json:"companyIds"`
+	DealIDs    []int64 `json:"dealIds"`
+	TicketIDs  []int64 `json:"ticketIds"`
+}
+
+type engagementAttachment struct {
+	ID int64 `json:"id"`
+}
+
+// engagementResponse is an engagement as returned by Hubspot. The metadata is decoded once the type is known.
+type engagementResponse struct {
+	Engagement struct {
+		ID          int64          `json:"id"`
+		Type        EngagementType `json:"type"`
+		OwnerID     int64          `json:"ownerId"`
+		Timestamp   timestamp      `json:"timestamp"`
+		CreatedAt   timestamp      `json:"createdAt"`
+		LastUpdated timestamp      `json:"lastUpdated"`
+	} `json:"engagement"`
+	Associations engagementAssociations `json:"associations"`
+	Attachments  []engagementAttachment `json:"attachments"`
+	Metadata     json.RawMessage        `json:"metadata"`
+}
+
+// engagementListResponse is a single page of engagements as returned by Hubspot
+type engagementListResponse struct {
+	Results []engagementResponse `json:"results"`
+	HasMore bool                 `json:"hasMore"`
+	Offset  int64                `json:"offset"`
+}
+
+// NewNote starts a note, timestamped now
+func NewNote(metadata NoteMetadata) *Engagement {
+	return newEngagement(metadata)
+}
+
+// NewEmail starts an email, timestamped now
+func NewEmail(metadata EmailMetadata) *Engagement {
+	return newEngagement(metadata)
+}
+
+// NewTask starts a task. The task is due now unless the Timestamp is changed.
+func NewTask(metadata TaskMetadata) *Engagement {
+	if metadata.Status == "" {
+		metadata.Status = TaskStatusNotStarted
+	}
+	return newEngagement(metadata)
+}
+
+// NewMeeting starts a meeting, timestamped with its start time if it has one
+func NewMeeting(metadata MeetingMetadata) *Engagement {
+	engagement := newEngagement(metadata)
+	if !metadata.StartTime.IsZero() {
+		engagement.Timestamp = metadata.StartTime
+	}
+	return engagement
+}
+
+// NewCall starts a call, timestamped now
+func NewCall(metadata CallMetadata) *Engagement {
+	return newEngagement(metadata)
+}
+
+func newEngagement(metadata EngagementMetadata) *Engagement {
+	return &Engagement{
+		Type:      metadata.engagementType(),
+		Timestamp: time.Now(),
+		Metadata:  metadata,
+	}
+}
+
+// CreateEngagement creates a new engagement, logged on the associated records. The ID of the engagement is filled in
+// once it has been created.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/engagements/create_engagement
+func (c *Client) CreateEngagement(ctx context.Context, engagement *Engagement) error {
+	if engagement.Metadata == nil {
+		return APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeEngagementMissingData,
+			Message:    "an engagement needs its metadata, so use one of the constructors such as NewNote",
+			Body:       nil,
+		}
+	}
+	send := engagement.request()
+	send.Engagement.Active = true
+	send.Engagement.Type = engagement.Metadata.engagementType()
+	send.Associations = &engagementAssociations{
+		ContactIDs: int64sOrEmpty(engagement.ContactIDs),
+		CompanyIDs: int64sOrEmpty(engagement.CompanyIDs),
+		DealIDs:    int64sOrEmpty(engagement.DealIDs),
+		TicketIDs:  int64sOrEmpty(engagement.TicketIDs),
+	}
+
+	result := engagementResponse{}
+	_, err := c.prepareCall(ctx, EndpointCreateEngagement, map[string]string{}, send, &result)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+			apiErr.SystemCode = CodeEngagementCouldNotBeCreated
+			return apiErr
+		}
+		return err
+	}
+	return engagement.populateEngagementFields(result)
+}
+
+// CreateEngagement calls Client.CreateEngagement on the DefaultClient with a background context
+func CreateEngagement(engagement *Engagement) error {
+	return DefaultClient().CreateEngagement(context.Background(), engagement)
+}
+
+// UpdateEngagement updates an existing engagement using its ID. Only what is set is changed: the owner, the timestamp,
+// the attachments when there are any, and the metadata fields that are not blank. The engagement is filled in with
+// what Hubspot returns.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/engagements/update_engagement-patch
+func (c *Client) UpdateEngagement(ctx context.Context, engagement *Engagement) error {
+	if engagement.ID == 0 {
+		return engagementIDZeroError("updating")
+	}
+
+	result := engagementResponse{}
+	_, err := c.prepareCall(ctx, EndpointUpdateEngagement, map[string]string{
+		":engagementID": fmt.Sprintf("%d", engagement.ID),
+	}, engagement.request(), &result)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeEngagementNotFound
+				return apiErr
+			}
+			apiErr.SystemCode = CodeEngagementCouldNotBeUpdated
+			return apiErr
+		}
+		return err
+	}
+	return engagement.populateEngagementFields(result)
+}
+
+// UpdateEngagement calls Client.UpdateEngagement on the DefaultClient with a background context
+func UpdateEngagement(engagement *Engagement) error {
+	return DefaultClient().UpdateEngagement(context.Background(), engagement)
+}
+
+// GetEngagementByID gets a single engagement by its ID, with its associations and metadata
+//
+// API Doc: https://developers.hubspot.com/docs/methods/engagements/get_engagement
+func (c *Client) GetEngagementByID(ctx context.Context, engagementID int64) (Engagement, error) {
+	engagement := Engagement{}
+	if engagementID == 0 {
+		return engagement, engagementIDZeroError("getting")
+	}
+	result := engagementResponse{}
+	_, err := c.prepareCall(ctx, EndpointGetEngagement, map[string]string{
+		":engagementID": fmt.Sprintf("%d", engagementID),
+	}, nil, &result)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeEngagementNotFound
+				return engagement, apiErr
+			}
+			apiErr.SystemCode = CodeGeneralError
+			return engagement, apiErr
+		}
+		return engagement, err
+	}
+	err = engagement.populateEngagementFields(result)
+	return engagement, err
+}
+
+// GetEngagementByID calls Client.GetEngagementByID on the DefaultClient with a background context
+func GetEngagementByID(engagementID int64) (Engagement, error) {
+	return DefaultClient().GetEngagementByID(context.Background(), engagementID)
+}
+
+// DeleteEngagementByID deletes a single engagement by its ID
+//
+// API Doc: https://developers.hubspot.com/docs/methods/engagements/delete-engagement
+func (c *Client) DeleteEngagementByID(ctx context.Context, engagementID int64) error {
+	if engagementID == 0 {
+		return engagementIDZeroError("deleting")
+	}
+	_, err := c.prepareCall(ctx, EndpointDeleteEngagement, map[string]string{
+		":engagementID": fmt.Sprintf("%d", engagementID),
+	}, nil, nil)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeEngagementNotFound
+				return apiErr
+			}
+			apiErr.SystemCode = CodeEngagementCouldNotBeDeleted
+			return apiErr
+		}
+	}
+	return err
+}
+
+// DeleteEngagementByID calls Client.DeleteEngagementByID on the DefaultClient with a background context
+func DeleteEngagementByID(engagementID int64) error {
+	return DefaultClient().DeleteEngagementByID(context.Background(), engagementID)
+}
+
+// EngagementIterator walks through a listing of engagements, fetching pages from Hubspot as they are needed. It is used
+// the same way as a ContactIterator.
+type EngagementIterator struct {
+	pager
+	page []Engagement
+}
+
+// Next advances to the next engagement. It returns false when there are no more engagements or an error occurred,
+// which is then available from Err.
+func (it *EngagementIterator) Next() bool {
+	return it.next()
+}
+
+// Engagement returns the current engagement. It is only valid after a call to Next returns true.
+func (it *EngagementIterator) Engagement() Engagement {
+	if i := it.current(); i >= 0 {
+		return it.page[i]
+	}
+	return Engagement{}
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *EngagementIterator) Err() error {
+	return it.err
+}
+
+// Offset returns a cursor to resume the listing from with EngagementListOptions.Offset. See pager.cursor for where it
+// points.
+func (it *EngagementIterator) Offset() int64 {
+	return it.offset()
+}
+
+// ListEngagements walks every engagement in the portal
+//
+// API Doc: https://developers.hubspot.com/docs/methods/engagements/get-all-engagements
+func (c *Client) ListEngagements(ctx context.Context, opts *EngagementListOptions) *EngagementIterator {
+	if opts == nil {
+		opts = &EngagementListOptions{}
+	}
+	return c.listEngagements(ctx, EndpointListEngagements, opts.Offset, func(offset int64) url.Values {
+		query := url.Values{}
+		query.Set("limit", fmt.Sprintf("%d", pageCount(opts.Count, engagementPageSize)))
+		if offset != 0 {
+			query.Set("offset", fmt.Sprintf("%d", offset))
+		}
+		return query
+	})
+}
+
+// ListEngagements calls Client.ListEngagements on the DefaultClient with a background context
+func ListEngagements(opts *EngagementListOptions) *EngagementIterator {
+	return DefaultClient().ListEngagements(context.Background(), opts)
+}
+
+// ListRecentEngagements walks the engagements created or modified in the last 30 days, or since opts.Since, newest
+// first. Hubspot stops the listing after 10,000 engagements.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/engagements/get-recent-engagements
+func (c *Client) ListRecentEngagements(ctx context.Context, opts *EngagementListOptions) *EngagementIterator {
+	if opts == nil {
+		opts = &EngagementListOptions{}
+	}
+	return c.listEngagements(ctx, EndpointListRecentEngagements, opts.Offset, func(offset int64) url.Values {
+		query := url.Values{}
+		query.Set("count", fmt.Sprintf("%d", pageCount(opts.Count, engagementRecentPageSize)))
+		if offset != 0 {
+			query.Set("offset", fmt.Sprintf("%d", offset))
+		}
+		if !opts.Since.IsZero() {
+			query.Set("since", fmt.Sprintf("%d", toMilliseconds(opts.Since)))
+		}
+		return query
+	})
+}
+
+// ListRecentEngagements calls Client.ListRecentEngagements on the DefaultClient with a background context
+func ListRecentEngagements(opts *EngagementListOptions) *EngagementIterator {
+	return DefaultClient().ListRecentEngagements(context.Background(), opts)
+}
+
+// listEngagements builds an iterator over one of the engagement listings, using the query built for each page
+func (c *Client) listEngagements(ctx context.Context, endpoint string, offset int64, query func(offset int64) url.Values) *EngagementIterator {
+	it := &EngagementIterator{}
+	it.pager = newPager(ctx, offset, func(ctx context.Context, offset int64) (int, int64, bool, error) {
+		result := engagementListResponse{}
+		_, err := c.prepareCall(ctx, endpoint, map[string]string{}, query(offset), &result)
+		if err != nil {
+			if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+				apiErr.SystemCode = CodeEngagementCouldNotBeListed
+				return 0, offset, false, apiErr
+			}
+			return 0, offset, false, err
+		}
+		it.page = make([]Engagement, len(result.Results))
+		for i := range result.Results {
+			// metadata that does not decode is left nil, as GetEngagementByID does, rather than ending the listing
+			it.page[i].populateEngagementFields(result.Results[i])
+		}
+		return len(it.page), result.Offset, result.HasMore, nil
+	})
+	return it
+}
+
+// AddContactToEngagement logs the engagement on the contact
+func (c *Client) AddContactToEngagement(ctx context.Context, engagementID, vid int64) error {
+	return c.Associate(ctx, engagementID, vid, AssociationEngagementToContact)
+}
+
+// AddContactToEngagement calls Client.AddContactToEngagement on the DefaultClient with a background context
+func AddContactToEngagement(engagementID, vid int64) error {
+	return DefaultClient().AddContactToEngagement(context.Background(), engagementID, vid)
+}
+
+// RemoveContactFromEngagement removes the engagement from the contact
+func (c *Client) RemoveContactFromEngagement(ctx context.Context, engagementID, vid int64) error {
+	return c.RemoveAssociation(ctx, engagementID, vid, AssociationEngagementToContact)
+}
+
+// RemoveContactFromEngagement calls Client.RemoveContactFromEngagement on the DefaultClient with a background context
+func RemoveContactFromEngagement(engagementID, vid int64) error {
+	return DefaultClient().RemoveContactFromEngagement(context.Background(), engagementID, vid)
+}
+
+// AddCompanyToEngagement logs the engagement on the company
+func (c *Client) AddCompanyToEngagement(ctx context.Context, engagementID, companyID int64) error {
+	return c.Associate(ctx, engagementID, companyID, AssociationEngagementToCompany)
+}
+
+// AddCompanyToEngagement calls Client.AddCompanyToEngagement on the DefaultClient with a background context
+func AddCompanyToEngagement(engagementID, companyID int64) error {
+	return DefaultClient().AddCompanyToEngagement(context.Background(), engagementID, companyID)
+}
+
+// RemoveCompanyFromEngagement removes the engagement from the company
+func (c *Client) RemoveCompanyFromEngagement(ctx context.Context, engagementID, companyID int64) error {
+	return c.RemoveAssociation(ctx, engagementID, companyID, AssociationEngagementToCompany)
+}
+
+// RemoveCompanyFromEngagement calls Client.RemoveCompanyFromEngagement on the DefaultClient with a background context
+func RemoveCompanyFromEngagement(engagementID, companyID int64) error {
+	return DefaultClient().RemoveCompanyFromEngagement(context.Background(), engagementID, companyID)
+}
+
+// AddDealToEngagement logs the engagement on the deal
+func (c *Client) AddDealToEngagement(ctx context.Context, engagementID, dealID int64) error {
+	return c.Associate(ctx, engagementID, dealID, AssociationEngagementToDeal)
+}
+
+// AddDealToEngagement calls Client.AddDealToEngagement on the DefaultClient with a background context
+func AddDealToEngagement(engagementID, dealID int64) error {
+	return DefaultClient().AddDealToEngagement(context.Background(), engagementID, dealID)
+}
+
+// RemoveDealFromEngagement removes the engagement from the deal
+func (c *Client) RemoveDealFromEngagement(ctx context.Context, engagementID, dealID int64) error {
+	return c.RemoveAssociation(ctx, engagementID, dealID, AssociationEngagementToDeal)
+}
+
+// RemoveDealFromEngagement calls Client.RemoveDealFromEngagement on the DefaultClient with a background context
+func RemoveDealFromEngagement(engagementID, dealID int64) error {
+	return DefaultClient().RemoveDealFromEngagement(context.Background(), engagementID, dealID)
+}
+
+// AddTicketToEngagement logs the engagement on the ticket
+func (c *Client) AddTicketToEngagement(ctx context.Context, engagementID, ticketID int64) error {
+	return c.Associate(ctx, engagementID, ticketID, AssociationEngagementToTicket)
+}
+
+// AddTicketToEngagement calls Client.AddTicketToEngagement on the DefaultClient with a background context
+func AddTicketToEngagement(engagementID, ticketID int64) error {
+	return DefaultClient().AddTicketToEngagement(context.Background(), engagementID, ticketID)
+}
+
+// RemoveTicketFromEngagement removes the engagement from the ticket
+func (c *Client) RemoveTicketFromEngagement(ctx context.Context, engagementID, ticketID int64) error {
+	return c.RemoveAssociation(ctx, engagementID, ticketID, AssociationEngagementToTicket)
+}
+
+// RemoveTicketFromEngagement calls Client.RemoveTicketFromEngagement on the DefaultClient with a background context
+func RemoveTicketFromEngagement(engagementID, ticketID int64) error {
+	return DefaultClient().RemoveTicketFromEngagement(context.Background(), engagementID, ticketID)
+}
+
+// request builds the body to send for the engagement, leaving out the associations
+func (engagement *Engagement) request() engagementRequest {
+	send := engagementRequest{
+		Engagement: engagementData{
+			OwnerID:   engagement.OwnerID,
+			Timestamp: optionalMilliseconds(engagement.Timestamp),
+		},
+		Metadata: engagement.Metadata,
+	}
+	for _, id := range engagement.AttachmentIDs {
+		send.Attachments = append(send.Attachments, engagementAttachment{ID: id})
+	}
+	return send
+}
+
+// populateEngagementFields fills in the engagement from Hubspot's representation of it
+func (engagement *Engagement) populateEngagementFields(result engagementResponse) error {
+	engagement.ID = result.Engagement.ID
+	engagement.Type = result.Engagement.Type
+	engagement.OwnerID = result.Engagement.OwnerID
+	engagement.Timestamp = result.Engagement.Timestamp.Time
+	engagement.CreatedAt = result.Engagement.CreatedAt.Time
+	engagement.UpdatedAt = result.Engagement.LastUpdated.Time
+	engagement.ContactIDs = result.Associations.ContactIDs
+	engagement.CompanyIDs = result.Associations.CompanyIDs
+	engagement.DealIDs = result.Associations.DealIDs
+	engagement.TicketIDs = result.Associations.TicketIDs
+	engagement.AttachmentIDs = nil
+	for _, attachment := range result.Attachments {
+		engagement.AttachmentIDs = append(engagement.AttachmentIDs, attachment.ID)
+	}
+	metadata, err := decodeEngagementMetadata(result.Engagement.Type, result.Metadata)
+	if err != nil {
+		return APIError{
+			HTTPCode:   http.StatusOK,
+			SystemCode: CodeResponseCouldNotBeDecoded,
+			Message:    fmt.Sprintf("the metadata of the engagement could not be decoded: %s", err.Error()),
+		}
+	}
+	engagement.Metadata = metadata
+	return nil
+}
+
+// engagementIDZeroError is returned when an engagement is needed but its ID is missing
+func engagementIDZeroError(action string) APIError {
+	return APIError{
+		HTTPCode:   http.StatusBadRequest,
+		SystemCode: CodeEngagementIDZero,
+		Message:    fmt.Sprintf("the ID for the engagement cannot be 0 when %s it", action),
+		Body:       nil,
+	}
+}
@@ -0,0 +1,220 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEngagementResponse = `{
+	"engagement": {"id": 29090716, "portalId": 62515, "active": true, "createdAt": 1577836800000, "lastUpdated": 1577840400000,
+		"ownerId": 5, "type": "MEETING", "timestamp": 1577836800000},
+	"associations": {"contactIds": [27], "companyIds": [], "dealIds": [151088], "ownerIds": [], "ticketIds": []},
+	"attachments": [{"id": 4241968539}],
+	"metadata": {"title": "Onboarding", "body": "Walk through the app", "startTime": 1577836800000, "endTime": 1577840400000}
+}`
+
+func TestEngagementConstructors(t *testing.T) {
+	note := NewNote(NoteMetadata{Body: "Called about the collar"})
+	assert.Equal(t, EngagementTypeNote, note.Type)
+	assert.False(t, note.Timestamp.IsZero())
+
+	task := NewTask(TaskMetadata{Subject: "Follow up"})
+	assert.Equal(t, EngagementTypeTask, task.Type)
+	assert.Equal(t, TaskStatusNotStarted, task.Metadata.(TaskMetadata).Status)
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	meeting := NewMeeting(MeetingMetadata{Title: "Onboarding", StartTime: start, EndTime: start.Add(time.Hour)})
+	assert.Equal(t, EngagementTypeMeeting, meeting.Type)
+	assert.True(t, start.Equal(meeting.Timestamp))
+	data, err := json.Marshal(meeting.Metadata)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"title": "Onboarding", "startTime": 1577836800000, "endTime": 1577840400000}`, string(data))
+	// a meeting can be moved to the epoch, but times that aren't set are left out
+	data, err = json.Marshal(MeetingMetadata{StartTime: time.Unix(0, 0)})
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"startTime": 0}`, string(data))
+	epoch := NewMeeting(MeetingMetadata{StartTime: time.Unix(0, 0)})
+	data, err = json.Marshal(epoch.request().Engagement)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"timestamp": 0}`, string(data))
+
+	call := NewCall(CallMetadata{ToNumber: "5551234567", Status: "COMPLETED", Duration: 90 * time.Second})
+	assert.Equal(t, EngagementTypeCall, call.Type)
+	data, err = json.Marshal(call.Metadata)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"toNumber": "5551234567", "status": "COMPLETED", "durationMilliseconds": 90000}`, string(data))
+	decoded, err := decodeEngagementMetadata(EngagementTypeCall, data)
+	require.Nil(t, err)
+	assert.Equal(t, call.Metadata, decoded)
+
+	email := NewEmail(EmailMetadata{
+		From:    &EmailAddress{Email: "support@wagz.com"},
+		To:      []EmailAddress{{Email: "test@wagz.com", FirstName: "Test"}},
+		Subject: "Your collar",
+	})
+	assert.Equal(t, EngagementTypeEmail, email.Type)
+	decoded, err = decodeEngagementMetadata("CONVERSATION_SESSION", []byte(`{"anything": true}`))
+	require.Nil(t, err)
+	assert.Nil(t, decoded)
+}
+
+func TestEngagementWrites(t *testing.T) {
+	status := http.StatusOK
+	var method, path string
+	var sent map[string]interface{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		sent = nil
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, testEngagementResponse)
+	})
+	defer server.Close()
+
+	err := client.CreateEngagement(context.Background(), &Engagement{ContactIDs: []int64{27}})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeEngagementMissingData, err.(APIError).SystemCode)
+	err = client.UpdateEngagement(context.Background(), NewNote(NoteMetadata{Body: "Updated"}))
+	require.NotNil(t, err)
+	assert.Equal(t, CodeEngagementIDZero, err.(APIError).SystemCode)
+	assert.Equal(t, "", path)
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	engagement := NewMeeting(MeetingMetadata{Title: "Onboarding", Body: "Walk through the app", StartTime: start, EndTime: start.Add(time.Hour)})
+	engagement.OwnerID = 5
+	engagement.ContactIDs = []int64{27}
+	engagement.DealIDs = []int64{151088}
+	engagement.AttachmentIDs = []int64{4241968539}
+	require.Nil(t, client.CreateEngagement(context.Background(), engagement))
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/engagements/v1/engagements", path)
+	assert.Equal(t, map[string]interface{}{
+		"active": true, "type": "MEETING", "ownerId": float64(5), "timestamp": float64(1577836800000),
+	}, sent["engagement"])
+	assert.Equal(t, map[string]interface{}{
+		"contactIds": []interface{}{float64(27)},
+		"companyIds": []interface{}{},
+		"dealIds":    []interface{}{float64(151088)},
+		"ticketIds":  []interface{}{},
+	}, sent["associations"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": float64(4241968539)}}, sent["attachments"])
+	assert.Equal(t, float64(1577840400000), sent["metadata"].(map[string]interface{})["endTime"])
+	assert.Equal(t, int64(29090716), engagement.ID)
+	assert.Equal(t, 2020, engagement.CreatedAt.Year())
+
+	update := Engagement{ID: 29090716, Metadata: MeetingMetadata{Body: "Went well"}}
+	require.Nil(t, client.UpdateEngagement(context.Background(), &update))
+	assert.Equal(t, http.MethodPatch, method)
+	assert.Equal(t, "/engagements/v1/engagements/29090716", path)
+	assert.Equal(t, map[string]interface{}{
+		"engagement": map[string]interface{}{},
+		"metadata":   map[string]interface{}{"body": "Went well"},
+	}, sent)
+	assert.Equal(t, "Onboarding", update.Metadata.(MeetingMetadata).Title)
+
+	status = http.StatusNotFound
+	err = client.UpdateEngagement(context.Background(), &update)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeEngagementNotFound, err.(APIError).SystemCode)
+	err = client.DeleteEngagementByID(context.Background(), 29090716)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeEngagementNotFound, err.(APIError).SystemCode)
+	assert.Equal(t, http.MethodDelete, method)
+
+	status = http.StatusBadRequest
+	err = client.CreateEngagement(context.Background(), engagement)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeEngagementCouldNotBeCreated, err.(APIError).SystemCode)
+}
+
+func TestEngagementLookups(t *testing.T) {
+	queries := []url.Values{}
+	var path string
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/engagements/v1/engagements/29090716":
+			fmt.Fprint(w, testEngagementResponse)
+		case "/engagements/v1/engagements/paged":
+			if r.URL.Query().Get("offset") == "" {
+				fmt.Fprintf(w, `{"results": [%s, {"engagement": {"id": 2, "type": "NOTE"}, "metadata": {"body": "Hi"}}], "hasMore": true, "offset": 2}`, testEngagementResponse)
+				return
+			}
+			fmt.Fprint(w, `{"results": [{"engagement": {"id": 3, "type": "TASK"}, "metadata": {"subject": "Call back", "status": "COMPLETED"}}], "hasMore": false, "offset": 3}`)
+		case "/engagements/v1/engagements/4":
+			fmt.Fprint(w, `{"engagement": {"id": 4, "type": "NOTE"}, "metadata": {"body": 7}}`)
+		case "/engagements/v1/engagements/recent/modified":
+			fmt.Fprint(w, `{"results": [{"engagement": {"id": 4, "type": "NOTE"}, "metadata": {"body": 7}}, {"engagement": {"id": 5, "type": "NOTE"}, "metadata": {"body": "Hi"}}], "hasMore": false, "offset": 2}`)
+		case "/crm-associations/v1/associations":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	engagement, err := client.GetEngagementByID(context.Background(), 29090716)
+	require.Nil(t, err)
+	assert.Equal(t, EngagementTypeMeeting, engagement.Type)
+	assert.Equal(t, []int64{151088}, engagement.DealIDs)
+	assert.Equal(t, []int64{4241968539}, engagement.AttachmentIDs)
+	meeting := engagement.Metadata.(MeetingMetadata)
+	assert.Equal(t, time.Hour, meeting.EndTime.Sub(meeting.StartTime))
+	_, err = client.GetEngagementByID(context.Background(), 1)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeEngagementNotFound, err.(APIError).SystemCode)
+	// a single engagement reports its metadata could not be decoded, but the rest of it is still there
+	engagement, err = client.GetEngagementByID(context.Background(), 4)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeResponseCouldNotBeDecoded, err.(APIError).SystemCode)
+	assert.Equal(t, int64(4), engagement.ID)
+	assert.Nil(t, engagement.Metadata)
+
+	queries = []url.Values{}
+	it := client.ListEngagements(context.Background(), nil)
+	types := []EngagementType{}
+	for it.Next() {
+		types = append(types, it.Engagement().Type)
+	}
+	require.Nil(t, it.Err())
+	assert.Equal(t, []EngagementType{EngagementTypeMeeting, EngagementTypeNote, EngagementTypeTask}, types)
+	assert.Equal(t, TaskStatusCompleted, it.page[0].Metadata.(TaskMetadata).Status)
+	assert.Equal(t, int64(3), it.Offset())
+	assert.Equal(t, "250", queries[0].Get("limit"))
+	assert.Equal(t, "2", queries[1].Get("offset"))
+
+	queries = []url.Values{}
+	since := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	it = client.ListRecentEngagements(context.Background(), &EngagementListOptions{Since: since, Count: 20})
+	// metadata that does not decode is left nil rather than ending the listing
+	ids := []int64{}
+	for it.Next() {
+		ids = append(ids, it.Engagement().ID)
+	}
+	require.Nil(t, it.Err())
+	assert.Equal(t, []int64{4, 5}, ids)
+	assert.Nil(t, it.page[0].Metadata)
+	assert.Equal(t, "20", queries[0].Get("count"))
+	assert.Equal(t, "1577836800000", queries[0].Get("since"))
+
+	require.Nil(t, client.AddContactToEngagement(context.Background(), 29090716, 27))
+	require.Nil(t, client.AddCompanyToEngagement(context.Background(), 29090716, 10444744))
+	require.Nil(t, client.AddDealToEngagement(context.Background(), 29090716, 151088))
+	require.Nil(t, client.AddTicketToEngagement(context.Background(), 29090716, 176602))
+	assert.Equal(t, "/crm-associations/v1/associations", path)
+	err = client.RemoveTicketFromEngagement(context.Background(), 29090716, 176602)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeAssociationCouldNotBeRemoved, err.(APIError).SystemCode)
+}
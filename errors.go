@@ -0,0 +1,161 @@
+package hubspot
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-resty/resty"
+)
+
+// Sentinel errors that can be checked for with errors.Is. An APIError matches the sentinel that fits its HTTPCode, so
+// callers don't have to remember which status codes Hubspot uses for what.
+var (
+	// ErrNotFound matches errors for records that don't exist (404)
+	ErrNotFound = errors.New("hubspot: not found")
+	// ErrRateLimited matches errors for calls that were rejected because a rate limit was reached (429)
+	ErrRateLimited = errors.New("hubspot: rate limited")
+	// ErrUnauthorized matches errors for calls that were not authenticated or not allowed (401 and 403)
+	ErrUnauthorized = errors.New("hubspot: unauthorized")
+	// ErrConflict matches errors for calls that conflict with an existing record, such as a duplicate email (409)
+	ErrConflict = errors.New("hubspot: conflict")
+	// ErrValidation matches errors for input that was rejected, by Hubspot or before calling Hubspot (400 and 422)
+	ErrValidation = errors.New("hubspot: validation failed")
+)
+
+// APIError is the error struct containing additional information regarding the error that occurred. The HTTPCode is the returned value from
+// Hubspot OR a 400 if there was an error prior to calling Hubspot. The SystemCode is a constant and you can check systemCodes.go for more information.
+//
+// Every error returned by the SDK for a failed call is an APIError value (never a pointer), so a type assertion or
+// errors.As with an APIError catches all of them. The remaining fields are filled in from Hubspot's error body when it
+// has one.
+type APIError struct {
+	HTTPCode   int
+	SystemCode string
+	Message    string
+	Body       *map[string]interface{}
+
+	// Status, Category and SubCategory classify the error, such as "error", "VALIDATION_ERROR" and "INVALID_EMAIL"
+	Status      string
+	Category    string
+	SubCategory string
+	// CorrelationID and RequestID identify the call; include them when asking Hubspot support about a failure
+	CorrelationID string
+	RequestID     string
+	// ValidationResults lists the properties that were rejected by the v1 and v2 APIs
+	ValidationResults []ValidationResult
+	// Errors lists the individual problems reported by the v3 APIs
+	Errors []ErrorDetail
+	// Err is the underlying error for failures that did not come from Hubspot, such as a cancelled context
+	Err error
+}
+
+// ValidationResult is a single property that Hubspot rejected
+type ValidationResult struct {
+	IsValid bool   `json:"isValid"`
+	Message string `json:"message"`
+	Error   string `json:"error"`
+	Name    string `json:"name"`
+}
+
+// ErrorDetail is a single problem reported by Hubspot
+type ErrorDetail struct {
+	Message     string                 `json:"message"`
+	In          string                 `json:"in"`
+	Code        string                 `json:"code"`
+	SubCategory string                 `json:"subCategory"`
+	Context     map[string]interface{} `json:"context"`
+}
+
+func (e APIError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the underlying error, so that errors.Is can see through to errors such as context.Canceled
+func (e APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches one of the sentinel errors
+func (e APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.HTTPCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.HTTPCode == http.StatusTooManyRequests
+	case ErrUnauthorized:
+		return e.HTTPCode == http.StatusUnauthorized || e.HTTPCode == http.StatusForbidden
+	case ErrConflict:
+		return e.HTTPCode == http.StatusConflict
+	case ErrValidation:
+		return e.HTTPCode == http.StatusBadRequest || e.HTTPCode == http.StatusUnprocessableEntity
+	}
+	return false
+}
+
+// errorResponse is the body Hubspot sends with a failed call. The v1 and v2 APIs and the v3 APIs use different fields
+// for the details, so both are decoded.
+type errorResponse struct {
+	Status            string             `json:"status"`
+	Message           string             `json:"message"`
+	Category          string             `json:"category"`
+	SubCategory       string             `json:"subCategory"`
+	CorrelationID     string             `json:"correlationId"`
+	RequestID         string             `json:"requestId"`
+	ValidationResults []ValidationResult `json:"validationResults"`
+	Errors            []ErrorDetail      `json:"errors"`
+}
+
+// newResponseError builds the APIError for a response with an error status code
+func newResponseError(response *resty.Response) APIError {
+	apiErr := APIError{
+		HTTPCode:   response.StatusCode(),
+		SystemCode: CodeHubspotError,
+		Message:    "error",
+	}
+
+	body := map[string]interface{}{}
+	if err := json.Unmarshal(response.Body(), &body); err == nil {
+		apiErr.Body = &body
+	}
+	parsed := errorResponse{}
+	if err := json.Unmarshal(response.Body(), &parsed); err == nil {
+		if parsed.Message != "" {
+			apiErr.Message = parsed.Message
+		}
+		apiErr.Status = parsed.Status
+		apiErr.Category = parsed.Category
+		apiErr.SubCategory = parsed.SubCategory
+		apiErr.CorrelationID = parsed.CorrelationID
+		apiErr.RequestID = parsed.RequestID
+		apiErr.ValidationResults = parsed.ValidationResults
+		apiErr.Errors = parsed.Errors
+	}
+	if apiErr.CorrelationID == "" {
+		apiErr.CorrelationID = response.Header().Get("X-HubSpot-Correlation-Id")
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = response.Header().Get("X-HubSpot-Request-Id")
+	}
+	return apiErr
+}
+
+// newRequestError builds the APIError for a call that failed before Hubspot could answer it. The query string is
+// removed from the URL in the error, since it holds the API key.
+func newRequestError(err error) APIError {
+	if urlErr, urlErrOK := err.(*url.Error); urlErrOK {
+		withoutQuery := *urlErr
+		if i := strings.Index(withoutQuery.URL, "?"); i >= 0 {
+			withoutQuery.URL = withoutQuery.URL[:i]
+		}
+		err = &withoutQuery
+	}
+	return APIError{
+		HTTPCode:   http.StatusInternalServerError,
+		SystemCode: CodeRequestError,
+		Message:    err.Error(),
+		Err:        err,
+	}
+}
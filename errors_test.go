@@ -0,0 +1,126 @@
+package hubspot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorParsesValidationResults(t *testing.T) {
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{
+			"status": "error",
+			"message": "Property values were not valid",
+			"correlationId": "3c1488ba-ac4c-4f0b-a5a5-63fb5d4a1b6d",
+			"requestId": "5b8f5dc1-df5e-4a69-9e47-8a9c6d7f9b9e",
+			"validationResults": [{
+				"isValid": false,
+				"message": "Property \"userId\" does not exist",
+				"error": "PROPERTY_DOESNT_EXIST",
+				"name": "userId"
+			}]
+		}`))
+	})
+	defer server.Close()
+
+	err := client.CreateOrUpdateContact(context.Background(), &Contact{Email: "test@test.com"})
+	require.NotNil(t, err)
+	apiErr := APIError{}
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, CodeContactCouldNotBeCreated, apiErr.SystemCode)
+	assert.Equal(t, "Property values were not valid", apiErr.Message)
+	assert.Equal(t, "3c1488ba-ac4c-4f0b-a5a5-63fb5d4a1b6d", apiErr.CorrelationID)
+	assert.Equal(t, "5b8f5dc1-df5e-4a69-9e47-8a9c6d7f9b9e", apiErr.RequestID)
+	require.Len(t, apiErr.ValidationResults, 1)
+	assert.Equal(t, "userId", apiErr.ValidationResults[0].Name)
+	assert.Equal(t, "PROPERTY_DOESNT_EXIST", apiErr.ValidationResults[0].Error)
+	assert.NotNil(t, apiErr.Body)
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.False(t, errors.Is(err, ErrNotFound))
+}
+
+func TestErrorParsesV3Errors(t *testing.T) {
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-HubSpot-Correlation-Id", "from-header")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{
+			"status": "error",
+			"message": "Contact already exists",
+			"category": "CONFLICT",
+			"subCategory": "OBJECT_ALREADY_EXISTS",
+			"errors": [{
+				"message": "Contact already exists. Existing ID: 42",
+				"code": "CONFLICT",
+				"context": {"id": ["42"]}
+			}]
+		}`))
+	})
+	defer server.Close()
+
+	_, err := client.GetContactByEmail(context.Background(), "test@test.com")
+	require.NotNil(t, err)
+	apiErr := APIError{}
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "CONFLICT", apiErr.Category)
+	assert.Equal(t, "OBJECT_ALREADY_EXISTS", apiErr.SubCategory)
+	assert.Equal(t, "from-header", apiErr.CorrelationID)
+	require.Len(t, apiErr.Errors, 1)
+	assert.Equal(t, "Contact already exists. Existing ID: 42", apiErr.Errors[0].Message)
+	assert.True(t, errors.Is(err, ErrConflict))
+}
+
+func TestErrorSentinels(t *testing.T) {
+	assert.True(t, errors.Is(APIError{HTTPCode: http.StatusNotFound}, ErrNotFound))
+	assert.True(t, errors.Is(APIError{HTTPCode: http.StatusTooManyRequests}, ErrRateLimited))
+	assert.True(t, errors.Is(APIError{HTTPCode: http.StatusUnauthorized}, ErrUnauthorized))
+	assert.True(t, errors.Is(APIError{HTTPCode: http.StatusForbidden}, ErrUnauthorized))
+	assert.True(t, errors.Is(APIError{HTTPCode: http.StatusConflict}, ErrConflict))
+	assert.True(t, errors.Is(APIError{HTTPCode: http.StatusUnprocessableEntity}, ErrValidation))
+	assert.False(t, errors.Is(APIError{HTTPCode: http.StatusInternalServerError}, ErrNotFound))
+
+	// errors raised before calling Hubspot match too
+	_, err := GetContactByEmail("")
+	assert.True(t, errors.Is(err, ErrValidation))
+}
+
+func TestTransportErrorsAreValues(t *testing.T) {
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := client.DeleteContactByVID(ctx, 42)
+	require.NotNil(t, err)
+	apiErr, cOK := err.(APIError)
+	require.True(t, cOK)
+	assert.Equal(t, CodeContactCouldNotBeDeleted, apiErr.SystemCode)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestTransportErrorsHideTheAPIKey(t *testing.T) {
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	})
+	defer server.Close()
+	client.config.HubspotAPIKey = "super-secret-api-key"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := client.DeleteContactByVID(ctx, 42)
+	require.NotNil(t, err)
+	apiErr := err.(APIError)
+	assert.NotContains(t, apiErr.Message, "super-secret-api-key")
+	assert.NotContains(t, apiErr.Err.Error(), "hapikey")
+	assert.Contains(t, apiErr.Message, "/contacts/v1/contact/vid/42")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
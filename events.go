@@ -1,6 +1,7 @@
 package hubspot
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"net/http"
@@ -21,6 +22,12 @@ type EventType struct {
 	ObjectType string `json:"objectType"`
 }
 
+// eventTypeResponse is returned by Hubspot when an event type is created. The applicationId comes back as a number, so
+// only the fields we need are decoded.
+type eventTypeResponse struct {
+	ID int64 `json:"id"`
+}
+
 // Event represents a timeline event
 type Event struct {
 	ID string `json:"id"`
@@ -43,8 +50,8 @@ type EventIFrame struct {
 // CreateNewEventType creates a new event type for events on the timeline
 //
 // API Doc: https://developers.hubspot.com/docs/methods/timeline/create-event-type
-func CreateNewEventType(input *EventType) error {
-	input.ApplicationID = Config.HubspotApplicationID
+func (c *Client) CreateNewEventType(ctx context.Context, input *EventType) error {
+	input.ApplicationID = c.config.HubspotApplicationID
 	if input.Name == "" || input.ObjectType == "" || input.ApplicationID == "" {
 		return APIError{
 			HTTPCode:   http.StatusBadRequest,
@@ -61,29 +68,31 @@ func CreateNewEventType(input *EventType) error {
 		input.DetailTemplate = "This event happened on {{#formatDate timestamp}}{{/formatDate}}"
 	}
 
-	ret, err := prepareCall(EndpointCreateEventType, map[string]string{}, input)
+	result := eventTypeResponse{}
+	_, err := c.prepareCall(ctx, EndpointCreateEventType, map[string]string{}, input, &result)
 
 	if err != nil {
-		if apiErr, apiErrOK := err.(APIError); apiErrOK {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
 			apiErr.SystemCode = CodeEventTypeCouldNotBeCreated
 			return apiErr
 		}
 		return err
 	}
-	if body, bodyOK := ret.Body.(map[string]interface{}); bodyOK {
-		if idF, idFOK := body["id"].(float64); idFOK {
-			input.ID = int64(idF)
-		}
-	}
+	input.ID = result.ID
 
 	return nil
 }
 
+// CreateNewEventType calls Client.CreateNewEventType on the DefaultClient with a background context
+func CreateNewEventType(input *EventType) error {
+	return DefaultClient().CreateNewEventType(context.Background(), input)
+}
+
 // CreateOrUpdateEvent creates or update an event on the timeline. The ID should be provided externally. If it isn't, this func will
 // generate a random ID based upon the current timestamp. This returns no data since the return header is a 204
 //
 // API Doc: https://developers.hubspot.com/docs/methods/timeline/create-or-update-event
-func CreateOrUpdateEvent(input *Event) error {
+func (c *Client) CreateOrUpdateEvent(ctx context.Context, input *Event) error {
 	// check for some required fields
 	if input.ObjectID == 0 || input.EventTypeID == 0 {
 		return APIError{
@@ -98,7 +107,7 @@ func CreateOrUpdateEvent(input *Event) error {
 		input.ID = fmt.Sprintf("%d%d%d", rand.Int63(), time.Now().Unix(), input.ObjectID)
 	}
 
-	_, err := prepareCall(EndpointCreateEvent, map[string]string{}, input)
+	_, err := c.prepareCall(ctx, EndpointCreateEvent, map[string]string{}, input, nil)
 
 	if err != nil {
 		if apiErr, apiErrOK := err.(APIError); apiErrOK {
@@ -110,13 +119,18 @@ func CreateOrUpdateEvent(input *Event) error {
 	return err
 }
 
+// CreateOrUpdateEvent calls Client.CreateOrUpdateEvent on the DefaultClient with a background context
+func CreateOrUpdateEvent(input *Event) error {
+	return DefaultClient().CreateOrUpdateEvent(context.Background(), input)
+}
+
 // DeleteEventTypeByID deletes an event type by the id
 //
 // API Doc: https://developers.hubspot.com/docs/methods/timeline/delete-event-type
-func DeleteEventTypeByID(eventTypeID int64) error {
-	_, err := prepareCall(EndpointDeleteEventType, map[string]string{
+func (c *Client) DeleteEventTypeByID(ctx context.Context, eventTypeID int64) error {
+	_, err := c.prepareCall(ctx, EndpointDeleteEventType, map[string]string{
 		":eventTypeID": fmt.Sprintf("%d", eventTypeID),
-	}, nil)
+	}, nil, nil)
 
 	if err != nil {
 		if apiErr, apiErrOK := err.(APIError); apiErrOK {
@@ -127,3 +141,8 @@ func DeleteEventTypeByID(eventTypeID int64) error {
 
 	return err
 }
+
+// DeleteEventTypeByID calls Client.DeleteEventTypeByID on the DefaultClient with a background context
+func DeleteEventTypeByID(eventTypeID int64) error {
+	return DefaultClient().DeleteEventTypeByID(context.Background(), eventTypeID)
+}
@@ -1,9 +1,8 @@
 module github.com/GetWagz/hubspot-sdk
 
-go 1.12
+go 1.13
 
 require (
-	github.com/fatih/structs v1.1.0
 	github.com/go-resty/resty v1.11.0
 	github.com/sirupsen/logrus v1.3.0
 	github.com/stretchr/testify v1.3.0
@@ -0,0 +1,194 @@
+package hubspot
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Logger receives the structured log entries written by a client. Secrets such as the API key, bearer tokens and the
+// client secret are redacted from the message and fields before they are passed on.
+type Logger interface {
+	Info(message string, fields map[string]interface{})
+	Warning(message string, fields map[string]interface{})
+	Error(message string, fields map[string]interface{})
+}
+
+// logrusLogger adapts a logrus logger to the Logger interface
+type logrusLogger struct {
+	logger logrus.FieldLogger
+}
+
+// NewLogrusLogger creates a Logger that writes to the passed in logrus logger or entry
+func NewLogrusLogger(logger logrus.FieldLogger) Logger {
+	return &logrusLogger{logger: logger}
+}
+
+func (l *logrusLogger) Info(message string, fields map[string]interface{}) {
+	l.logger.WithFields(logrus.Fields(fields)).Info(message)
+}
+
+func (l *logrusLogger) Warning(message string, fields map[string]interface{}) {
+	l.logger.WithFields(logrus.Fields(fields)).Warning(message)
+}
+
+func (l *logrusLogger) Error(message string, fields map[string]interface{}) {
+	l.logger.WithFields(logrus.Fields(fields)).Error(message)
+}
+
+// standardLogger adapts a logger from the standard library to the Logger interface
+type standardLogger struct {
+	logger *log.Logger
+}
+
+// NewStandardLogger creates a Logger that writes to the passed in standard library logger, one line per entry with the
+// fields sorted by name
+func NewStandardLogger(logger *log.Logger) Logger {
+	return &standardLogger{logger: logger}
+}
+
+func (l *standardLogger) Info(message string, fields map[string]interface{}) {
+	l.print("INFO", message, fields)
+}
+
+func (l *standardLogger) Warning(message string, fields map[string]interface{}) {
+	l.print("WARNING", message, fields)
+}
+
+func (l *standardLogger) Error(message string, fields map[string]interface{}) {
+	l.print("ERROR", message, fields)
+}
+
+func (l *standardLogger) print(level, message string, fields map[string]interface{}) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	line := fmt.Sprintf("%s %s", level, message)
+	for _, k := range keys {
+		line += fmt.Sprintf(" %s=%v", k, fields[k])
+	}
+	l.logger.Println(line)
+}
+
+// NopLogger is a Logger that discards everything
+type NopLogger struct{}
+
+// Info discards the entry
+func (NopLogger) Info(message string, fields map[string]interface{}) {}
+
+// Warning discards the entry
+func (NopLogger) Warning(message string, fields map[string]interface{}) {}
+
+// Error discards the entry
+func (NopLogger) Error(message string, fields map[string]interface{}) {}
+
+// newDefaultLogger creates the logger used by a client when none is configured. Each client gets its own logrus
+// instance so that the formatting of the host application's global logrus logger is left alone.
+func newDefaultLogger() Logger {
+	logger := logrus.New()
+	logger.Formatter = &logrus.JSONFormatter{}
+	return NewLogrusLogger(logger)
+}
+
+// redactedValue replaces any secret that is logged
+const redactedValue = "[REDACTED]"
+
+var (
+	// secretFieldNames are field names whose values are always redacted
+	secretFieldNames = map[string]bool{
+		"hapikey":       true,
+		"authorization": true,
+		"access_token":  true,
+		"accesstoken":   true,
+		"refresh_token": true,
+		"refreshtoken":  true,
+		"client_secret": true,
+		"clientsecret":  true,
+		"code":          true,
+		"token":         true,
+	}
+	// secretPatterns find secrets embedded in longer strings, such as URLs in error messages
+	secretPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)((?:hapikey|access_token|refresh_token|client_secret|code)=)[^&\s"']+`),
+		regexp.MustCompile(`(?i)(bearer\s+)[^\s"']+`),
+	}
+)
+
+// redactor removes secrets from log entries. Besides the well known field names and patterns, the exact secrets the
+// client holds are replaced wherever they appear.
+type redactor struct {
+	secrets []string
+}
+
+// redactor builds a redactor for the secrets the client currently knows about
+func (c *Client) redactor() redactor {
+	secrets := []string{
+		c.config.HubspotAPIKey,
+		c.config.HubspotClientSecret,
+		c.config.HubSpotOAuthRefreshToken,
+	}
+	// the token mutex may be held by our caller, so the token's secrets are tracked separately
+	c.secretsMutex.Lock()
+	secrets = append(secrets, c.tokenSecrets...)
+	c.secretsMutex.Unlock()
+
+	r := redactor{}
+	for _, secret := range secrets {
+		// very short values, like the demo key, would mangle ordinary words
+		if len(secret) >= 8 {
+			r.secrets = append(r.secrets, secret)
+		}
+	}
+	return r
+}
+
+func (r redactor) redactString(value string) string {
+	for _, pattern := range secretPatterns {
+		value = pattern.ReplaceAllString(value, "${1}"+redactedValue)
+	}
+	for _, secret := range r.secrets {
+		value = strings.Replace(value, secret, redactedValue, -1)
+	}
+	return value
+}
+
+func (r redactor) redactFields(fields map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if secretFieldNames[strings.ToLower(k)] {
+			redacted[k] = redactedValue
+			continue
+		}
+		redacted[k] = r.redactValue(v)
+	}
+	return redacted
+}
+
+func (r redactor) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, int, int32, int64, float32, float64, time.Duration, time.Time:
+		return v
+	case string:
+		return r.redactString(v)
+	case map[string]interface{}:
+		return r.redactFields(v)
+	case map[string]string:
+		fields := make(map[string]interface{}, len(v))
+		for k, s := range v {
+			fields[k] = s
+		}
+		return r.redactFields(fields)
+	case error:
+		return r.redactString(v.Error())
+	default:
+		// we can't know what is inside, so flatten it to a string we can scrub
+		return r.redactString(fmt.Sprintf("%+v", v))
+	}
+}
@@ -0,0 +1,142 @@
+package hubspot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLogger keeps every entry so tests can inspect them
+type recordingLogger struct {
+	mutex   sync.Mutex
+	entries []map[string]interface{}
+}
+
+func (l *recordingLogger) record(level, message string, fields map[string]interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	entry := map[string]interface{}{
+		"level":   level,
+		"message": message,
+	}
+	for k, v := range fields {
+		entry[k] = v
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func (l *recordingLogger) Info(message string, fields map[string]interface{}) {
+	l.record("info", message, fields)
+}
+
+func (l *recordingLogger) Warning(message string, fields map[string]interface{}) {
+	l.record("warning", message, fields)
+}
+
+func (l *recordingLogger) Error(message string, fields map[string]interface{}) {
+	l.record("error", message, fields)
+}
+
+func TestLoggerReceivesCallFields(t *testing.T) {
+	calls := 0
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"vid": 42,
+		})
+	})
+	defer server.Close()
+	logger := &recordingLogger{}
+	client.Config().Logging = true
+	client.Config().Logger = logger
+	client.Config().Retry = testRetryPolicy()
+
+	_, err := client.GetContactByEmail(context.Background(), "test@test.com")
+	require.Nil(t, err)
+
+	callEntries := []map[string]interface{}{}
+	for _, entry := range logger.entries {
+		if entry["key"] == "api_call" {
+			callEntries = append(callEntries, entry)
+		}
+	}
+	require.Len(t, callEntries, 2)
+	assert.Equal(t, "warning", callEntries[0]["level"])
+	assert.Equal(t, http.StatusBadGateway, callEntries[0]["status"])
+	assert.Equal(t, 1, callEntries[0]["attempt"])
+	assert.Equal(t, "info", callEntries[1]["level"])
+	assert.Equal(t, http.MethodGet, callEntries[1]["method"])
+	assert.Equal(t, "/contacts/v1/contact/email/test@test.com/profile", callEntries[1]["path"])
+	assert.Equal(t, http.StatusOK, callEntries[1]["status"])
+	assert.Equal(t, 2, callEntries[1]["attempt"])
+	assert.NotNil(t, callEntries[1]["latency"])
+}
+
+func TestLoggerRedactsSecrets(t *testing.T) {
+	logger := &recordingLogger{}
+	client := NewClient(&ConfigStruct{
+		RootURL:             "https://api.hubapi.com/",
+		HubspotAPIKey:       "super-secret-api-key",
+		HubspotClientSecret: "super-secret-client-secret",
+		Logging:             true,
+		Logger:              logger,
+	})
+	client.tokenMutex.Lock()
+	client.setToken(OAuthToken{AccessToken: "super-secret-access-token"})
+	client.tokenMutex.Unlock()
+
+	client.log("error", "test", "failed calling https://api.hubapi.com/x?hapikey=super-secret-api-key&userId=1", map[string]interface{}{
+		"err":           errors.New("Get https://api.hubapi.com/x?hapikey=another-key: no such host"),
+		"header":        "Bearer super-secret-access-token",
+		"client_secret": "super-secret-client-secret",
+		"query": map[string]string{
+			"hapikey": "super-secret-api-key",
+			"userId":  "1",
+		},
+		"nested": struct{ Secret string }{"super-secret-client-secret"},
+		"status": 500,
+	})
+
+	require.Len(t, logger.entries, 1)
+	entry := logger.entries[0]
+	everything := ""
+	for _, v := range entry {
+		everything += " " + strings.TrimSpace(fmtValue(v))
+	}
+	assert.NotContains(t, everything, "super-secret")
+	assert.NotContains(t, everything, "another-key")
+	assert.Contains(t, entry["message"], "userId=1")
+	assert.Equal(t, "1", entry["query"].(map[string]interface{})["userId"])
+	assert.Equal(t, 500, entry["status"])
+}
+
+func fmtValue(v interface{}) string {
+	contents, _ := json.Marshal(v)
+	return string(contents)
+}
+
+func TestStandardLogger(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := NewStandardLogger(log.New(buffer, "", 0))
+	logger.Warning("something happened", map[string]interface{}{
+		"status": 429,
+		"key":    "api_call",
+	})
+	assert.Equal(t, "WARNING something happened key=api_call status=429\n", buffer.String())
+
+	// the no-op logger really does nothing
+	NopLogger{}.Error("ignored", nil)
+}
@@ -1,19 +1,28 @@
 package hubspot
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/go-resty/resty"
 )
 
-var oAuthToken = OAuthToken{}
+// oAuthTokenExpiryMargin is how long before its expiry an access token is refreshed, so that a call never goes out
+// with a token that lapses in flight
+const oAuthTokenExpiryMargin = 5 * time.Minute
 
 // OAuthToken represents a current oAuth token for accessing calls requiring oAuth
 type OAuthToken struct {
-	RefreshToken string
-	AccessToken  string
-	ExpiresIn    int
+	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	// ExpiresAt is the absolute time the access token lapses; it is zero if the expiry is unknown
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 // OAuthResponse represents the oAuth response when trying to refresh a token
@@ -24,40 +33,385 @@ type OAuthResponse struct {
 	TokenType    string `json:"token_type"`
 }
 
-func renewOAuthToken() (*OAuthToken, error) {
-	resty.SetContentLength(true)
-	response, err := resty.R().
-		SetHeader("Content-Type", "application/x-www-form-urlencoded").
-		SetFormData(map[string]string{
-			"grant_type":    "refresh_token",
-			"refresh_token": Config.HubSpotOAuthRefreshToken,
-			"client_id":     Config.HubspotClientID,
-			"client_secret": Config.HubspotClientSecret,
-		}).
-		Post("https://api.hubapi.com/oauth/v1/token")
+// OAuthTokenInfo is the metadata Hubspot holds about an access or refresh token, including the portal (hub) it was
+// issued for and the user who installed the app
+type OAuthTokenInfo struct {
+	Token     string   `json:"token"`
+	User      string   `json:"user"`
+	UserID    int64    `json:"user_id"`
+	HubDomain string   `json:"hub_domain"`
+	HubID     int64    `json:"hub_id"`
+	AppID     int64    `json:"app_id"`
+	ClientID  string   `json:"client_id"`
+	Scopes    []string `json:"scopes"`
+	ExpiresIn int      `json:"expires_in"`
+	TokenType string   `json:"token_type"`
+}
+
+// token converts the response into an OAuthToken, working out when it expires
+func (r OAuthResponse) token() OAuthToken {
+	token := OAuthToken{
+		AccessToken:  r.AccessToken,
+		RefreshToken: r.RefreshToken,
+		ExpiresIn:    r.ExpiresIn,
+	}
+	if token.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return token
+}
+
+// Valid returns true if the token has an access token that will not expire within the refresh margin
+func (t OAuthToken) Valid() bool {
+	if t.AccessToken == "" {
+		return false
+	}
+	return t.ExpiresAt.IsZero() || time.Now().Add(oAuthTokenExpiryMargin).Before(t.ExpiresAt)
+}
 
+// accessToken returns a valid access token, refreshing it first if it is missing or about to expire
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	return c.ensureAccessToken(ctx, "")
+}
+
+// refreshRejectedToken refreshes the access token after Hubspot rejected it. If another goroutine or process has
+// already replaced the rejected token, the replacement is used instead of refreshing again.
+func (c *Client) refreshRejectedToken(ctx context.Context, rejected string) (string, error) {
+	return c.ensureAccessToken(ctx, rejected)
+}
+
+// ensureAccessToken returns a valid access token other than the rejected one. It first looks at the token held by the
+// client, then at the token store, and only refreshes if neither is usable. Refreshes are serialized, so concurrent
+// callers wait for the one refresh in flight rather than starting their own; with a token store, the store's lock
+// extends that to every process sharing the store.
+func (c *Client) ensureAccessToken(ctx context.Context, rejected string) (string, error) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	usable := func(token OAuthToken) bool {
+		return token.Valid() && (rejected == "" || token.AccessToken != rejected)
+	}
+	if usable(c.token) {
+		return c.token.AccessToken, nil
+	}
+
+	store := c.config.TokenStore
+	if store != nil {
+		found, err := c.loadStoredToken(ctx, usable)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return c.token.AccessToken, nil
+		}
+		unlock, err := store.Lock(ctx, c.config.HubspotPortalID)
+		if err != nil {
+			return "", err
+		}
+		defer unlock()
+		// another process may have refreshed the token while we waited for the lock
+		found, err = c.loadStoredToken(ctx, usable)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return c.token.AccessToken, nil
+		}
+	}
+
+	token, err := c.renewOAuthTokenLocked(ctx)
 	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// setToken replaces the client's token and remembers its secrets so they can be redacted from the logs. The caller must
+// hold the token mutex.
+func (c *Client) setToken(token OAuthToken) {
+	c.token = token
+	c.secretsMutex.Lock()
+	c.tokenSecrets = []string{token.AccessToken, token.RefreshToken}
+	c.secretsMutex.Unlock()
+}
+
+// loadStoredToken reads the portal's token from the token store. It returns true if the stored token is usable, in
+// which case it is now the client's token. The caller must hold the token mutex.
+func (c *Client) loadStoredToken(ctx context.Context, usable func(OAuthToken) bool) (bool, error) {
+	stored, err := c.config.TokenStore.GetToken(ctx, c.config.HubspotPortalID)
+	if err != nil || stored == nil {
+		return false, err
+	}
+	if stored.RefreshToken != "" {
+		// even if the access token is stale, the stored refresh token is the most recent one
+		c.token.RefreshToken = stored.RefreshToken
+	}
+	if !usable(*stored) {
+		return false, nil
+	}
+	c.setToken(*stored)
+	return true, nil
+}
+
+// renewOAuthToken exchanges the refresh token for a new access token and stores it on the client
+func (c *Client) renewOAuthToken(ctx context.Context) (*OAuthToken, error) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	return c.renewOAuthTokenLocked(ctx)
+}
+
+// renewOAuthTokenLocked does the work of renewOAuthToken; the caller must hold the token mutex
+func (c *Client) renewOAuthTokenLocked(ctx context.Context) (*OAuthToken, error) {
+	refreshToken := c.token.RefreshToken
+	if refreshToken == "" {
+		refreshToken = c.config.HubSpotOAuthRefreshToken
+	}
+	if refreshToken == "" {
+		return nil, APIError{
+			HTTPCode:   http.StatusUnauthorized,
+			SystemCode: CodeOAuthTokenUnavailable,
+			Message:    "no oAuth refresh token is configured",
+		}
+	}
+
+	newToken, err := c.requestOAuthToken(ctx, map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": refreshToken,
+		"client_id":     c.config.HubspotClientID,
+		"client_secret": c.config.HubspotClientSecret,
+	})
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK {
+			if apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+				apiErr.SystemCode = CodeOAuthTokenUnavailable
+			}
+			apiErr.Message = fmt.Sprintf("Could not refresh that token: %s", apiErr.Message)
+			return nil, apiErr
+		}
 		return nil, err
 	}
-	code := response.StatusCode()
-	if code != 200 {
-		return nil, errors.New("Could not refresh that token")
+	if newToken.RefreshToken == "" {
+		newToken.RefreshToken = refreshToken
+	}
+	c.setToken(*newToken)
+	if c.config.TokenStore != nil {
+		// the token is still good for this client, so a failure to save it is not fatal
+		if err := c.config.TokenStore.SaveToken(ctx, c.config.HubspotPortalID, *newToken); err != nil {
+			c.log("error", "hubspot_sdk_oauth_not_saved", "could not save the refreshed oAuth token to the token store", err)
+		}
+	}
+	c.log("info", "hubspot_sdk_oauth_refreshed", "refreshed the oAuth access token", map[string]interface{}{
+		"expiresAt": newToken.ExpiresAt,
+	})
+	return newToken, nil
+}
+
+// AuthorizeURL builds the URL a HubSpot user should be sent to in order to install the app in their portal. The state is
+// passed back to the redirect URI untouched and should be used to guard against request forgery. Optional scopes are
+// granted only if the portal supports them.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/oauth2/initiate-oauth-integration
+func (c *Client) AuthorizeURL(scopes []string, optionalScopes []string, state string) string {
+	query := url.Values{}
+	query.Set("client_id", c.config.HubspotClientID)
+	query.Set("redirect_uri", c.config.HubspotOAuthRedirectURI)
+	query.Set("scope", strings.Join(scopes, " "))
+	if len(optionalScopes) > 0 {
+		query.Set("optional_scope", strings.Join(optionalScopes, " "))
+	}
+	if state != "" {
+		query.Set("state", state)
+	}
+	return fmt.Sprintf("%s?%s", c.config.HubspotOAuthAuthorizeURL, query.Encode())
+}
+
+// AuthorizeURL calls Client.AuthorizeURL on the DefaultClient
+func AuthorizeURL(scopes []string, optionalScopes []string, state string) string {
+	return DefaultClient().AuthorizeURL(scopes, optionalScopes, state)
+}
+
+// ExchangeAuthorizationCode exchanges the code HubSpot passed to the redirect URI for a new pair of tokens. The token is
+// returned rather than stored, since it belongs to the portal that installed the app; use GetAccessTokenInfo to find
+// out which portal that is.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/oauth2/get-access-and-refresh-tokens
+func (c *Client) ExchangeAuthorizationCode(ctx context.Context, code string) (*OAuthToken, error) {
+	if code == "" {
+		return nil, APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeOAuthMissingData,
+			Message:    "the authorization code is required",
+		}
+	}
+	token, err := c.requestOAuthToken(ctx, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     c.config.HubspotClientID,
+		"client_secret": c.config.HubspotClientSecret,
+		"redirect_uri":  c.config.HubspotOAuthRedirectURI,
+		"code":          code,
+	})
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded && apiErr.SystemCode != CodeOAuthTokenUnavailable {
+			apiErr.SystemCode = CodeOAuthCouldNotExchangeCode
+			return nil, apiErr
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// ExchangeAuthorizationCode calls Client.ExchangeAuthorizationCode on the DefaultClient with a background context
+func ExchangeAuthorizationCode(code string) (*OAuthToken, error) {
+	return DefaultClient().ExchangeAuthorizationCode(context.Background(), code)
+}
+
+// GetAccessTokenInfo gets the metadata for an access token, including the hub ID and user it was issued for
+//
+// API Doc: https://developers.hubspot.com/docs/methods/oauth2/get-access-token-information
+func (c *Client) GetAccessTokenInfo(ctx context.Context, accessToken string) (*OAuthTokenInfo, error) {
+	return c.getTokenInfo(ctx, EndpointGetOAuthAccessToken, accessToken)
+}
+
+// GetAccessTokenInfo calls Client.GetAccessTokenInfo on the DefaultClient with a background context
+func GetAccessTokenInfo(accessToken string) (*OAuthTokenInfo, error) {
+	return DefaultClient().GetAccessTokenInfo(context.Background(), accessToken)
+}
+
+// GetRefreshTokenInfo gets the metadata for a refresh token, including the hub ID and user it was issued for
+//
+// API Doc: https://developers.hubspot.com/docs/methods/oauth2/get-refresh-token-information
+func (c *Client) GetRefreshTokenInfo(ctx context.Context, refreshToken string) (*OAuthTokenInfo, error) {
+	return c.getTokenInfo(ctx, EndpointGetOAuthRefreshToken, refreshToken)
+}
+
+// GetRefreshTokenInfo calls Client.GetRefreshTokenInfo on the DefaultClient with a background context
+func GetRefreshTokenInfo(refreshToken string) (*OAuthTokenInfo, error) {
+	return DefaultClient().GetRefreshTokenInfo(context.Background(), refreshToken)
+}
+
+// RevokeRefreshToken deletes a refresh token, for example when a customer uninstalls the app. Access tokens issued
+// from it keep working until they expire.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/oauth2/delete-refresh-token
+func (c *Client) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	if refreshToken == "" {
+		return APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeOAuthMissingData,
+			Message:    "the refresh token is required",
+		}
+	}
+	_, err := c.callOAuth(ctx, EndpointDeleteOAuthRefreshToken, map[string]string{
+		":token": url.PathEscape(refreshToken),
+	}, nil)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK {
+			if apiErr.HTTPCode == http.StatusNotFound {
+				apiErr.SystemCode = CodeOAuthTokenNotFound
+				return apiErr
+			}
+			apiErr.SystemCode = CodeOAuthCouldNotRevoke
+			return apiErr
+		}
+	}
+	return err
+}
+
+// RevokeRefreshToken calls Client.RevokeRefreshToken on the DefaultClient with a background context
+func RevokeRefreshToken(refreshToken string) error {
+	return DefaultClient().RevokeRefreshToken(context.Background(), refreshToken)
+}
+
+// getTokenInfo looks up the metadata of an access or refresh token
+func (c *Client) getTokenInfo(ctx context.Context, endpoint, token string) (*OAuthTokenInfo, error) {
+	if token == "" {
+		return nil, APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeOAuthMissingData,
+			Message:    "the token is required",
+		}
+	}
+	response, err := c.callOAuth(ctx, endpoint, map[string]string{
+		":token": url.PathEscape(token),
+	}, nil)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK {
+			if apiErr.HTTPCode == http.StatusNotFound {
+				apiErr.SystemCode = CodeOAuthTokenNotFound
+				return nil, apiErr
+			}
+			apiErr.SystemCode = CodeGeneralError
+			return nil, apiErr
+		}
+		return nil, err
+	}
+	info := OAuthTokenInfo{}
+	if err := decodeResult(oauthReturn(response), &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// requestOAuthToken calls the token endpoint with the passed in grant and returns the resulting token
+func (c *Client) requestOAuthToken(ctx context.Context, form map[string]string) (*OAuthToken, error) {
+	response, err := c.callOAuth(ctx, EndpointCreateOAuthToken, nil, form)
+	if err != nil {
+		return nil, err
 	}
 	parsedResponse := OAuthResponse{}
-	json.Unmarshal(response.Body(), &parsedResponse)
-	newToken := OAuthToken{
-		AccessToken:  parsedResponse.AccessToken,
-		RefreshToken: parsedResponse.RefreshToken,
-		ExpiresIn:    parsedResponse.ExpiresIn,
+	if err := decodeResult(oauthReturn(response), &parsedResponse); err != nil {
+		return nil, err
+	}
+	// a token without an access token would be sent as an empty bearer token on every call
+	if parsedResponse.AccessToken == "" {
+		return nil, APIError{
+			HTTPCode:   response.StatusCode(),
+			SystemCode: CodeOAuthTokenUnavailable,
+			Message:    "Hubspot did not return an access token",
+		}
+	}
+	token := parsedResponse.token()
+	return &token, nil
+}
+
+// oauthReturn wraps the response from an oAuth endpoint so it can be decoded in the same way as any other call
+func oauthReturn(response *resty.Response) *APIReturn {
+	return &APIReturn{
+		HTTPCode: response.StatusCode(),
+		Raw:      json.RawMessage(response.Body()),
 	}
-	oAuthToken = newToken
-	return &newToken, nil
 }
 
-func init() {
-	// get a new oauth token
-	_, err := renewOAuthToken()
+// callOAuth makes a call to one of Hubspot's oAuth endpoints. These endpoints authenticate with the data in the call
+// itself, so unlike makeCall no API key or bearer token is sent.
+func (c *Client) callOAuth(ctx context.Context, endpoint string, pathParams map[string]string, form map[string]string) (*resty.Response, error) {
+	info, infoFound := c.endpoints[endpoint]
+	if !infoFound {
+		return nil, APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodeRequestEndpointNotFound,
+			Message:    "Could not find that endpoint",
+		}
+	}
+	parsedPath := strings.TrimPrefix(info.Path, "/")
+	for k, v := range pathParams {
+		parsedPath = strings.Replace(parsedPath, k, v, -1)
+	}
+
+	request := c.http.R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json")
+	if form != nil {
+		request.
+			SetContentLength(true).
+			SetHeader("Content-Type", "application/x-www-form-urlencoded").
+			SetFormData(form)
+	}
+	response, err := request.Execute(info.Method, fmt.Sprintf("%s%s", c.config.RootURL, parsedPath))
 	if err != nil {
-		log("error", "hubspot_sdk_no_oauth", "could not get oauth token; you will not be able to use any calls that require oAuth", err)
+		return nil, newRequestError(err)
+	}
+	if response.StatusCode() >= http.StatusMultipleChoices {
+		return nil, newResponseError(response)
 	}
+	return response, nil
 }
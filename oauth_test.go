@@ -0,0 +1,267 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// oAuthTestServer stands in for Hubspot's token endpoint and a single oAuth protected endpoint. It hands out numbered
+// access tokens and only accepts the most recently issued one.
+type oAuthTestServer struct {
+	refreshes int32
+	current   string
+	mutex     sync.Mutex
+}
+
+func (s *oAuthTestServer) handle(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/oauth/v1/token" {
+		r.ParseForm()
+		if r.Form.Get("refresh_token") != "refresh-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// slow down the refresh a bit so concurrent callers pile up behind it
+		time.Sleep(20 * time.Millisecond)
+		count := atomic.AddInt32(&s.refreshes, 1)
+		s.mutex.Lock()
+		s.current = fmt.Sprintf("access-token-%d", count)
+		s.mutex.Unlock()
+		json.NewEncoder(w).Encode(OAuthResponse{
+			AccessToken:  s.current,
+			RefreshToken: "refresh-token",
+			ExpiresIn:    21600,
+			TokenType:    "bearer",
+		})
+		return
+	}
+
+	s.mutex.Lock()
+	current := s.current
+	s.mutex.Unlock()
+	if r.Header.Get("Authorization") != "Bearer "+current || !strings.HasPrefix(current, "access-token-") {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newOAuthTestClient() (*Client, *oAuthTestServer, func()) {
+	tokenServer := &oAuthTestServer{}
+	client, server := newTestClient(tokenServer.handle)
+	client.Config().HubSpotOAuthRefreshToken = "refresh-token"
+	client.Config().Retry = testRetryPolicy()
+	return client, tokenServer, server.Close
+}
+
+func TestOAuthRefreshesMissingAndExpiredTokens(t *testing.T) {
+	client, tokenServer, closer := newOAuthTestClient()
+	defer closer()
+
+	event := Event{ObjectID: 1, EventTypeID: 2}
+	err := client.CreateOrUpdateEvent(context.Background(), &event)
+	require.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenServer.refreshes))
+	assert.False(t, client.token.ExpiresAt.IsZero())
+
+	// a valid token is reused
+	err = client.CreateOrUpdateEvent(context.Background(), &event)
+	require.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenServer.refreshes))
+
+	// a token about to expire is refreshed before the call goes out
+	client.token.ExpiresAt = time.Now().Add(time.Minute)
+	err = client.CreateOrUpdateEvent(context.Background(), &event)
+	require.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&tokenServer.refreshes))
+}
+
+func TestOAuthRefreshesOnUnauthorized(t *testing.T) {
+	client, tokenServer, closer := newOAuthTestClient()
+	defer closer()
+
+	// Hubspot no longer accepts this token, even though we think it is still valid
+	client.token = OAuthToken{
+		AccessToken: "revoked",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	err := client.CreateOrUpdateEvent(context.Background(), &Event{ObjectID: 1, EventTypeID: 2})
+	require.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenServer.refreshes))
+	assert.Equal(t, "access-token-1", client.token.AccessToken)
+}
+
+func TestOAuthRefreshesAreSerialized(t *testing.T) {
+	client, tokenServer, closer := newOAuthTestClient()
+	defer closer()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := client.CreateOrUpdateEvent(context.Background(), &Event{ObjectID: 1, EventTypeID: 2})
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenServer.refreshes))
+}
+
+func TestOAuthRefreshFailure(t *testing.T) {
+	client, _, closer := newOAuthTestClient()
+	defer closer()
+	client.Config().HubSpotOAuthRefreshToken = "bad-token"
+
+	err := client.CreateOrUpdateEvent(context.Background(), &Event{ObjectID: 1, EventTypeID: 2})
+	require.NotNil(t, err)
+	apiErr, cOK := err.(APIError)
+	require.True(t, cOK)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.HTTPCode)
+	assert.Equal(t, CodeEventCouldNotBeCreated, apiErr.SystemCode)
+	assert.Contains(t, apiErr.Message, "Could not refresh that token")
+}
+
+func TestOAuthAuthorizationCodeFlow(t *testing.T) {
+	revoked := false
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/oauth/v1/token":
+			r.ParseForm()
+			if r.Form.Get("grant_type") != "authorization_code" || r.Form.Get("code") != "good-code" ||
+				r.Form.Get("redirect_uri") != "https://example.com/callback" || r.Form.Get("client_secret") != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"status":  "BAD_AUTH_CODE",
+					"message": "missing or unknown auth code",
+				})
+				return
+			}
+			json.NewEncoder(w).Encode(OAuthResponse{
+				AccessToken:  "portal-access-token",
+				RefreshToken: "portal-refresh-token",
+				ExpiresIn:    21600,
+			})
+		case r.URL.Path == "/oauth/v1/access-tokens/portal-access-token" && r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"token":      "portal-access-token",
+				"user":       "test@test.com",
+				"hub_domain": "test.com",
+				"scopes":     []string{"contacts", "timeline"},
+				"hub_id":     62515,
+				"app_id":     456,
+				"expires_in": 21588,
+				"user_id":    123,
+				"token_type": "access",
+			})
+		case r.URL.Path == "/oauth/v1/refresh-tokens/portal-refresh-token" && r.Method == http.MethodDelete:
+			revoked = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"status":  "NOT_FOUND",
+				"message": "not found",
+			})
+		}
+	})
+	defer server.Close()
+	client.Config().HubspotClientID = "client-id"
+	client.Config().HubspotClientSecret = "secret"
+	client.Config().HubspotOAuthRedirectURI = "https://example.com/callback"
+
+	authorizeURL, err := url.Parse(client.AuthorizeURL([]string{"contacts", "timeline"}, []string{"content"}, "xyz"))
+	require.Nil(t, err)
+	assert.Equal(t, "app.hubspot.com", authorizeURL.Host)
+	assert.Equal(t, "client-id", authorizeURL.Query().Get("client_id"))
+	assert.Equal(t, "contacts timeline", authorizeURL.Query().Get("scope"))
+	assert.Equal(t, "content", authorizeURL.Query().Get("optional_scope"))
+	assert.Equal(t, "https://example.com/callback", authorizeURL.Query().Get("redirect_uri"))
+	assert.Equal(t, "xyz", authorizeURL.Query().Get("state"))
+
+	_, err = client.ExchangeAuthorizationCode(context.Background(), "")
+	require.NotNil(t, err)
+	assert.Equal(t, CodeOAuthMissingData, err.(APIError).SystemCode)
+
+	_, err = client.ExchangeAuthorizationCode(context.Background(), "bad-code")
+	require.NotNil(t, err)
+	apiErr, cOK := err.(APIError)
+	require.True(t, cOK)
+	assert.Equal(t, http.StatusBadRequest, apiErr.HTTPCode)
+	assert.Equal(t, CodeOAuthCouldNotExchangeCode, apiErr.SystemCode)
+	assert.Equal(t, "missing or unknown auth code", apiErr.Message)
+
+	token, err := client.ExchangeAuthorizationCode(context.Background(), "good-code")
+	require.Nil(t, err)
+	assert.Equal(t, "portal-access-token", token.AccessToken)
+	assert.Equal(t, "portal-refresh-token", token.RefreshToken)
+	assert.True(t, token.Valid())
+
+	info, err := client.GetAccessTokenInfo(context.Background(), token.AccessToken)
+	require.Nil(t, err)
+	assert.Equal(t, int64(62515), info.HubID)
+	assert.Equal(t, "test@test.com", info.User)
+	assert.Equal(t, []string{"contacts", "timeline"}, info.Scopes)
+
+	_, err = client.GetRefreshTokenInfo(context.Background(), "unknown")
+	require.NotNil(t, err)
+	assert.Equal(t, CodeOAuthTokenNotFound, err.(APIError).SystemCode)
+
+	err = client.RevokeRefreshToken(context.Background(), token.RefreshToken)
+	require.Nil(t, err)
+	assert.True(t, revoked)
+}
+
+func TestOAuthRejectsBadTokenResponses(t *testing.T) {
+	tokenBody := `<html>Service Unavailable</html>`
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/v1/token":
+			fmt.Fprint(w, tokenBody)
+		case "/oauth/v1/access-tokens/some-token":
+			fmt.Fprint(w, `not json`)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	defer server.Close()
+	store := NewMemoryTokenStore()
+	client.Config().HubSpotOAuthRefreshToken = "refresh-token"
+	client.Config().TokenStore = store
+	client.Config().HubspotPortalID = "62515"
+
+	err := client.CreateOrUpdateEvent(context.Background(), &Event{ObjectID: 1, EventTypeID: 2})
+	require.NotNil(t, err)
+	assert.Contains(t, err.(APIError).Message, "could not be decoded")
+
+	_, err = client.ExchangeAuthorizationCode(context.Background(), "good-code")
+	require.NotNil(t, err)
+	assert.Equal(t, CodeResponseCouldNotBeDecoded, err.(APIError).SystemCode)
+
+	tokenBody = `{"refresh_token": "refresh-token", "expires_in": 21600}`
+	_, err = client.ExchangeAuthorizationCode(context.Background(), "good-code")
+	require.NotNil(t, err)
+	assert.Equal(t, CodeOAuthTokenUnavailable, err.(APIError).SystemCode)
+	err = client.CreateOrUpdateEvent(context.Background(), &Event{ObjectID: 1, EventTypeID: 2})
+	require.NotNil(t, err)
+	assert.Contains(t, err.(APIError).Message, "did not return an access token")
+
+	// nothing unusable was saved for later calls to pick up
+	saved, err := store.GetToken(context.Background(), "62515")
+	require.Nil(t, err)
+	assert.Nil(t, saved)
+
+	_, err = client.GetAccessTokenInfo(context.Background(), "some-token")
+	require.NotNil(t, err)
+	assert.Equal(t, CodeResponseCouldNotBeDecoded, err.(APIError).SystemCode)
+}
@@ -0,0 +1,225 @@
+package hubspot
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ObjectProperty is a single property to send for a company, deal, ticket or other CRM object
+type ObjectProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ObjectPropertyValue is a property of a CRM object as returned by Hubspot. Versions is only filled in when the property
+// history was asked for, and is ordered newest first.
+type ObjectPropertyValue struct {
+	Value     string
+	Timestamp time.Time
+	// Source is what set the value, such as "CRM_UI", "API" or "IMPORT"
+	Source   string
+	SourceID string
+	Versions []ObjectPropertyVersion
+}
+
+// ObjectPropertyVersion is a single value a property has had, along with where it came from
+type ObjectPropertyVersion struct {
+	Value     string
+	Timestamp time.Time
+	Source    string
+	SourceID  string
+}
+
+// ObjectProperties holds every property Hubspot returned for a CRM object, keyed by the property name
+type ObjectProperties map[string]ObjectPropertyValue
+
+// Has reports whether Hubspot returned the property
+func (p ObjectProperties) Has(name string) bool {
+	_, found := p[name]
+	return found
+}
+
+// Get returns the current value of the property, or a blank string if Hubspot did not return it
+func (p ObjectProperties) Get(name string) string {
+	return p[name].Value
+}
+
+// Int returns the current value of a number property as an integer. A blank or missing property is 0.
+func (p ObjectProperties) Int(name string) (int64, error) {
+	return propertyInt(name, p.Get(name))
+}
+
+// Float returns the current value of a number property. A blank or missing property is 0.
+func (p ObjectProperties) Float(name string) (float64, error) {
+	return propertyFloat(name, p.Get(name))
+}
+
+// Bool returns the current value of a checkbox property. A blank or missing property is false.
+func (p ObjectProperties) Bool(name string) (bool, error) {
+	return propertyBool(name, p.Get(name))
+}
+
+// Time returns the current value of a date or datetime property. A blank or missing property is the zero time.
+func (p ObjectProperties) Time(name string) (time.Time, error) {
+	return propertyTime(name, p.Get(name))
+}
+
+// Options returns the selected options of a multi-select property
+func (p ObjectProperties) Options(name string) []string {
+	return propertyOptions(p.Get(name))
+}
+
+// Versions returns the history of the property, newest first
+func (p ObjectProperties) Versions(name string) []ObjectPropertyVersion {
+	return p[name].Versions
+}
+
+// Unmarshal fills in your own tagged struct from the properties, as described in UnmarshalProperties
+func (p ObjectProperties) Unmarshal(v interface{}) error {
+	return UnmarshalProperties(p.values(), v)
+}
+
+// objectPropertyResponse is a single property of a CRM object as returned by Hubspot
+type objectPropertyResponse struct {
+	Value     PropertyValue                   `json:"value"`
+	Timestamp timestamp                       `json:"timestamp"`
+	Source    string                          `json:"source"`
+	SourceID  PropertyValue                   `json:"sourceId"`
+	Versions  []objectPropertyVersionResponse `json:"versions"`
+}
+
+// objectPropertyVersionResponse is a single version in the history of a property
+type objectPropertyVersionResponse struct {
+	Value     PropertyValue `json:"value"`
+	Timestamp timestamp     `json:"timestamp"`
+	Source    string        `json:"source"`
+	SourceID  PropertyValue `json:"sourceId"`
+}
+
+// newObjectProperties converts the properties Hubspot returned
+func newObjectProperties(response map[string]objectPropertyResponse) ObjectProperties {
+	props := make(ObjectProperties, len(response))
+	for name, property := range response {
+		value := ObjectPropertyValue{
+			Value:     property.Value.String(),
+			Timestamp: property.Timestamp.Time,
+			Source:    property.Source,
+			SourceID:  property.SourceID.String(),
+		}
+		for _, version := range property.Versions {
+			value.Versions = append(value.Versions, ObjectPropertyVersion{
+				Value:     version.Value.String(),
+				Timestamp: version.Timestamp.Time,
+				Source:    version.Source,
+				SourceID:  version.SourceID.String(),
+			})
+		}
+		props[name] = value
+	}
+	return props
+}
+
+// values returns the current value of each property
+func (p ObjectProperties) values() map[string]string {
+	values := make(map[string]string, len(p))
+	for name, property := range p {
+		values[name] = property.Value
+	}
+	return values
+}
+
+// objectPropertyList builds the properties to send for a CRM object from its tagged fields, any additional properties
+// and the properties to clear
+func objectPropertyList(v interface{}, additional *[]ObjectProperty, toClear []string) ([]ObjectProperty, error) {
+	fields, err := marshalPropertyList(v)
+	if err != nil {
+		return nil, err
+	}
+	if additional != nil {
+		for _, p := range *additional {
+			fields = append(fields, property{Name: p.Name, Value: p.Value})
+		}
+	}
+	fields = clearProperties(fields, toClear)
+
+	props := make([]ObjectProperty, len(fields))
+	for i, p := range fields {
+		props[i] = ObjectProperty{Name: p.Name, Value: p.Value}
+	}
+	return props, nil
+}
+
+// appendObjectProperties adds the properties of a tagged struct to a CRM object's additional properties
+func appendObjectProperties(additional **[]ObjectProperty, v interface{}) error {
+	props, err := marshalPropertyList(v)
+	if err != nil {
+		return err
+	}
+	if *additional == nil {
+		*additional = &[]ObjectProperty{}
+	}
+	for _, p := range props {
+		**additional = append(**additional, ObjectProperty{Name: p.Name, Value: p.Value})
+	}
+	return nil
+}
+
+func propertyInt(name, value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		// Hubspot stores numbers as decimals, so whole numbers can come back as 42.0
+		f, floatErr := strconv.ParseFloat(value, 64)
+		if floatErr != nil || f != float64(int64(f)) {
+			return 0, propertyError(name, err.Error())
+		}
+		i = int64(f)
+	}
+	return i, nil
+}
+
+func propertyFloat(name, value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, propertyError(name, err.Error())
+	}
+	return f, nil
+}
+
+func propertyBool(name, value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, propertyError(name, err.Error())
+	}
+	return b, nil
+}
+
+func propertyTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := parsePropertyTime(value)
+	if err != nil {
+		return time.Time{}, propertyError(name, err.Error())
+	}
+	return t, nil
+}
+
+func propertyOptions(value string) []string {
+	options := []string{}
+	for _, option := range strings.Split(value, multiSelectSeparator) {
+		if option != "" {
+			options = append(options, option)
+		}
+	}
+	return options
+}
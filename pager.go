@@ -0,0 +1,104 @@
+package hubspot
+
+import "context"
+
+// pageFetcher fetches the page at the offset, returning how many items were on it, the offset of the following page and
+// whether there is one. It is for the APIs whose cursor is a single number, such as companies.
+type pageFetcher func(ctx context.Context, offset int64) (count int, next int64, hasMore bool, err error)
+
+// cursorFetcher fetches the page at the cursor, returning how many items were on it, the cursor of the following page
+// and whether there is one. The items themselves are kept by the iterator that owns the pager. The cursors are compared
+// with == to spot one that does not move, so they must be comparable.
+type cursorFetcher func(ctx context.Context, cursor interface{}) (count int, next interface{}, hasMore bool, err error)
+
+// pager does the bookkeeping for the iterators over Hubspot's paged APIs, which return a cursor for the following page
+// along with a has-more flag
+type pager struct {
+	ctx   context.Context
+	fetch cursorFetcher
+
+	count      int
+	index      int
+	pageCursor interface{}
+	nextCursor interface{}
+	hasMore    bool
+	err        error
+}
+
+// newPager returns a pager over an API that pages with a numeric offset
+func newPager(ctx context.Context, offset int64, fetch pageFetcher) pager {
+	return newCursorPager(ctx, offset, func(ctx context.Context, cursor interface{}) (int, interface{}, bool, error) {
+		count, next, hasMore, err := fetch(ctx, cursor.(int64))
+		return count, next, hasMore, err
+	})
+}
+
+// newCursorPager returns a pager starting from the cursor
+func newCursorPager(ctx context.Context, cursor interface{}, fetch cursorFetcher) pager {
+	return pager{
+		ctx:        ctx,
+		fetch:      fetch,
+		index:      -1,
+		pageCursor: cursor,
+		nextCursor: cursor,
+		hasMore:    true,
+	}
+}
+
+// next advances to the next item, fetching another page if needed
+func (p *pager) next() bool {
+	if p.err != nil {
+		return false
+	}
+	p.index++
+	// Hubspot can return an empty page that still has more after it, so keep going until we find an item
+	for p.index >= p.count {
+		if !p.hasMore {
+			return false
+		}
+		count, next, hasMore, err := p.fetch(p.ctx, p.nextCursor)
+		if err != nil {
+			p.err = err
+			return false
+		}
+		p.count = count
+		p.index = 0
+		p.pageCursor = p.nextCursor
+		p.nextCursor = next
+		// guard against looping forever if the cursor does not move
+		p.hasMore = hasMore && next != p.pageCursor
+	}
+	return true
+}
+
+// current returns the index of the current item in the page, or -1 if there isn't one
+func (p *pager) current() int {
+	if p.index < 0 || p.index >= p.count {
+		return -1
+	}
+	return p.index
+}
+
+// cursor returns where to resume from. While there is a current item, including the last one on its page, it points at
+// the start of that page, so an item that was not finished before the cursor was saved is read again. It only moves on
+// once next has gone past the page. Resuming may repeat a few items but will never skip one.
+func (p *pager) cursor() interface{} {
+	if p.index >= 0 && p.index < p.count {
+		return p.pageCursor
+	}
+	return p.nextCursor
+}
+
+// offset returns the cursor of a pager made by newPager, or zero for an iterator that failed before it started
+func (p *pager) offset() int64 {
+	offset, _ := p.cursor().(int64)
+	return offset
+}
+
+// pageCount returns the page size to ask for, defaulting to and capped at the most the endpoint allows
+func pageCount(count, max int) int {
+	if count <= 0 || count > max {
+		return max
+	}
+	return count
+}
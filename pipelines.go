@@ -0,0 +1,569 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PipelineObjectType is the kind of object a pipeline is for
+type PipelineObjectType string
+
+// The object types that have pipelines
+const (
+	PipelineObjectTypeDeals   PipelineObjectType = "deals"
+	PipelineObjectTypeTickets PipelineObjectType = "tickets"
+)
+
+// Pipeline is a set of stages that deals or tickets move through. The IDs of pipelines and stages differ from portal to
+// portal, apart from the built in pipelines, so look them up by label with a PipelineCache instead of hard-coding them.
+type Pipeline struct {
+	ID           string          `json:"id"`
+	Label        string          `json:"label"`
+	DisplayOrder int             `json:"displayOrder"`
+	Stages       []PipelineStage `json:"stages"`
+	Archived     bool            `json:"archived"`
+	CreatedAt    time.Time       `json:"createdAt"`
+	UpdatedAt    time.Time       `json:"updatedAt"`
+}
+
+// PipelineStage is a single stage of a pipeline.
+//
+// Metadata holds the settings for the kind of object the pipeline is for. Deal stages need a "probability" between
+// "0.0" and "1.0", and ticket stages need a "ticketState" of "OPEN" or "CLOSED".
+type PipelineStage struct {
+	ID           string            `json:"id"`
+	Label        string            `json:"label"`
+	DisplayOrder int               `json:"displayOrder"`
+	Metadata     map[string]string `json:"metadata"`
+	Archived     bool              `json:"archived"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	UpdatedAt    time.Time         `json:"updatedAt"`
+}
+
+// pipelineRequest is the body to create or update a pipeline. Stages are only sent when creating it.
+type pipelineRequest struct {
+	Label        string                 `json:"label"`
+	DisplayOrder int                    `json:"displayOrder"`
+	Stages       []pipelineStageRequest `json:"stages,omitempty"`
+}
+
+// pipelineStageRequest is the body to create or update a stage
+type pipelineStageRequest struct {
+	Label        string            `json:"label"`
+	DisplayOrder int               `json:"displayOrder"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
+
+// pipelineListResponse is the pipelines, or the stages of a pipeline, as returned by Hubspot
+type pipelineListResponse struct {
+	Results []Pipeline `json:"results"`
+}
+
+type pipelineStageListResponse struct {
+	Results []PipelineStage `json:"results"`
+}
+
+// ListPipelines gets every pipeline for the object type, along with their stages. Archived pipelines are left out.
+//
+// These use the v3 pipelines API, which adds calls for a single pipeline and for the stages. The same pipelines can be
+// managed through the v1 CRM pipelines API with the V1 functions, such as ListPipelinesV1.
+//
+// API Doc: https://developers.hubspot.com/docs/api/crm/pipelines
+func (c *Client) ListPipelines(ctx context.Context, objectType PipelineObjectType) ([]Pipeline, error) {
+	result := pipelineListResponse{}
+	_, err := c.prepareCall(ctx, EndpointListPipelines, map[string]string{
+		":objectType": string(objectType),
+	}, map[string]string{
+		"archived": "false",
+	}, &result)
+	if err != nil {
+		return []Pipeline{}, pipelineError(err, CodePipelineNotFound, CodePipelineCouldNotBeListed)
+	}
+	if result.Results == nil {
+		result.Results = []Pipeline{}
+	}
+	return result.Results, nil
+}
+
+// ListPipelines calls Client.ListPipelines on the DefaultClient with a background context
+func ListPipelines(objectType PipelineObjectType) ([]Pipeline, error) {
+	return DefaultClient().ListPipelines(context.Background(), objectType)
+}
+
+// GetPipeline gets a single pipeline by its ID, along with its stages
+//
+// API Doc: https://developers.hubspot.com/docs/api/crm/pipelines
+func (c *Client) GetPipeline(ctx context.Context, objectType PipelineObjectType, pipelineID string) (Pipeline, error) {
+	pipeline := Pipeline{}
+	if pipelineID == "" {
+		return pipeline, pipelineMissingDataError("the ID of the pipeline is required")
+	}
+	_, err := c.prepareCall(ctx, EndpointGetPipeline, map[string]string{
+		":objectType": string(objectType),
+		":pipelineID": pipelineID,
+	}, nil, &pipeline)
+	if err != nil {
+		return pipeline, pipelineError(err, CodePipelineNotFound, CodeGeneralError)
+	}
+	return pipeline, nil
+}
+
+// GetPipeline calls Client.GetPipeline on the DefaultClient with a background context
+func GetPipeline(objectType PipelineObjectType, pipelineID string) (Pipeline, error) {
+	return DefaultClient().GetPipeline(context.Background(), objectType, pipelineID)
+}
+
+// CreatePipeline creates a new pipeline with its stages. The pipeline is filled in with what Hubspot returns, including
+// the IDs of the pipeline and its stages.
+//
+// API Doc: https://developers.hubspot.com/docs/api/crm/pipelines
+func (c *Client) CreatePipeline(ctx context.Context, objectType PipelineObjectType, pipeline *Pipeline) error {
+	if pipeline.Label == "" || len(pipeline.Stages) == 0 {
+		return pipelineMissingDataError("a pipeline needs a label and at least one stage")
+	}
+	send := pipelineRequest{
+		Label:        pipeline.Label,
+		DisplayOrder: pipeline.DisplayOrder,
+		Stages:       make([]pipelineStageRequest, len(pipeline.Stages)),
+	}
+	for i, stage := range pipeline.Stages {
+		if stage.Label == "" {
+			return pipelineMissingDataError("every stage needs a label")
+		}
+		send.Stages[i] = stage.request()
+	}
+	_, err := c.prepareCall(ctx, EndpointCreatePipeline, map[string]string{
+		":objectType": string(objectType),
+	}, send, pipeline)
+	if err != nil {
+		return pipelineError(err, CodePipelineNotFound, CodePipelineCouldNotBeCreated)
+	}
+	return nil
+}
+
+// CreatePipeline calls Client.CreatePipeline on the DefaultClient with a background context
+func CreatePipeline(objectType PipelineObjectType, pipeline *Pipeline) error {
+	return DefaultClient().CreatePipeline(context.Background(), objectType, pipeline)
+}
+
+// UpdatePipeline changes the label and display order of a pipeline. The stages are changed with the stage functions,
+// such as UpdatePipelineStage.
+//
+// API Doc: https://developers.hubspot.com/docs/api/crm/pipelines
+func (c *Client) UpdatePipeline(ctx context.Context, objectType PipelineObjectType, pipeline *Pipeline) error {
+	if pipeline.ID == "" || pipeline.Label == "" {
+		return pipelineMissingDataError("the ID and label of the pipeline are required")
+	}
+	_, err := c.prepareCall(ctx, EndpointUpdatePipeline, map[string]string{
+		":objectType": string(objectType),
+		":pipelineID": pipeline.ID,
+	}, pipelineRequest{
+		Label:        pipeline.Label,
+		DisplayOrder: pipeline.DisplayOrder,
+	}, pipeline)
+	if err != nil {
+		return pipelineError(err, CodePipelineNotFound, CodePipelineCouldNotBeUpdated)
+	}
+	return nil
+}
+
+// UpdatePipeline calls Client.UpdatePipeline on the DefaultClient with a background context
+func UpdatePipeline(objectType PipelineObjectType, pipeline *Pipeline) error {
+	return DefaultClient().UpdatePipeline(context.Background(), objectType, pipeline)
+}
+
+// DeletePipeline deletes a pipeline by its ID. Hubspot archives it rather than removing it outright.
+//
+// API Doc: https://developers.hubspot.com/docs/api/crm/pipelines
+func (c *Client) DeletePipeline(ctx context.Context, objectType PipelineObjectType, pipelineID string) error {
+	if pipelineID == "" {
+		return pipelineMissingDataError("the ID of the pipeline is required")
+	}
+	_, err := c.prepareCall(ctx, EndpointDeletePipeline, map[string]string{
+		":objectType": string(objectType),
+		":pipelineID": pipelineID,
+	}, nil, nil)
+	if err != nil {
+		return pipelineError(err, CodePipelineNotFound, CodePipelineCouldNotBeDeleted)
+	}
+	return nil
+}
+
+// DeletePipeline calls Client.DeletePipeline on the DefaultClient with a background context
+func DeletePipeline(objectType PipelineObjectType, pipelineID string) error {
+	return DefaultClient().DeletePipeline(context.Background(), objectType, pipelineID)
+}
+
+// ListPipelineStages gets the stages of a pipeline. Archived stages are left out.
+//
+// API Doc: https://developers.hubspot.com/docs/api/crm/pipelines
+func (c *Client) ListPipelineStages(ctx context.Context, objectType PipelineObjectType, pipelineID string) ([]PipelineStage, error) {
+	if pipelineID == "" {
+		return []PipelineStage{}, pipelineMissingDataError("the ID of the pipeline is required")
+	}
+	result := pipelineStageListResponse{}
+	_, err := c.prepareCall(ctx, EndpointListPipelineStages, map[string]string{
+		":objectType": string(objectType),
+		":pipelineID": pipelineID,
+	}, map[string]string{
+		"archived": "false",
+	}, &result)
+	if err != nil {
+		return []PipelineStage{}, pipelineError(err, CodePipelineNotFound, CodePipelineCouldNotBeListed)
+	}
+	if result.Results == nil {
+		result.Results = []PipelineStage{}
+	}
+	return result.Results, nil
+}
+
+// ListPipelineStages calls Client.ListPipelineStages on the DefaultClient with a background context
+func ListPipelineStages(objectType PipelineObjectType, pipelineID string) ([]PipelineStage, error) {
+	return DefaultClient().ListPipelineStages(context.Background(), objectType, pipelineID)
+}
+
+// GetPipelineStage gets a single stage of a pipeline by its ID
+//
+// API Doc: https://developers.hubspot.com/docs/api/crm/pipelines
+func (c *Client) GetPipelineStage(ctx context.Context, objectType PipelineObjectType, pipelineID, stageID string) (PipelineStage, error) {
+	stage := PipelineStage{}
+	if pipelineID == "" || stageID == "" {
+		return stage, pipelineMissingDataError("the IDs of the pipeline and stage are required")
+	}
+	_, err := c.prepareCall(ctx, EndpointGetPipelineStage, map[string]string{
+		":objectType": string(objectType),
+		":pipelineID": pipelineID,
+		":stageID":    stageID,
+	}, nil, &stage)
+	if err != nil {
+		return stage, pipelineError(err, CodePipelineStageNotFound, CodeGeneralError)
+	}
+	return stage, nil
+}
+
+// GetPipelineStage calls Client.GetPipelineStage on the DefaultClient with a background context
+func GetPipelineStage(objectType PipelineObjectType, pipelineID, stageID string) (PipelineStage, error) {
+	return DefaultClient().GetPipelineStage(context.Background(), objectType, pipelineID, stageID)
+}
+
+// CreatePipelineStage adds a stage to a pipeline. The stage is filled in with what Hubspot returns, including its ID.
+//
+// API Doc: https://developers.hubspot.com/docs/api/crm/pipelines
+func (c *Client) CreatePipelineStage(ctx context.Context, objectType PipelineObjectType, pipelineID string, stage *PipelineStage) error {
+	if pipelineID == "" || stage.Label == "" {
+		return pipelineMissingDataError("the ID of the pipeline and the label of the stage are required")
+	}
+	_, err := c.prepareCall(ctx, EndpointCreatePipelineStage, map[string]string{
+		":objectType": string(objectType),
+		":pipelineID": pipelineID,
+	}, stage.request(), stage)
+	if err != nil {
+		return pipelineError(err, CodePipelineNotFound, CodePipelineStageCouldNotBeCreated)
+	}
+	return nil
+}
+
+// CreatePipelineStage calls Client.CreatePipelineStage on the DefaultClient with a background context
+func CreatePipelineStage(objectType PipelineObjectType, pipelineID string, stage *PipelineStage) error {
+	return DefaultClient().CreatePipelineStage(context.Background(), objectType, pipelineID, stage)
+}
+
+// UpdatePipelineStage changes the label, display order and metadata of a stage
+//
+// API Doc: https://developers.hubspot.com/docs/api/crm/pipelines
+func (c *Client) UpdatePipelineStage(ctx context.Context, objectType PipelineObjectType, pipelineID string, stage *PipelineStage) error {
+	if pipelineID == "" || stage.ID == "" || stage.Label == "" {
+		return pipelineMissingDataError("the ID of the pipeline and the ID and label of the stage are required")
+	}
+	_, err := c.prepareCall(ctx, EndpointUpdatePipelineStage, map[string]string{
+		":objectType": string(objectType),
+		":pipelineID": pipelineID,
+		":stageID":    stage.ID,
+	}, stage.request(), stage)
+	if err != nil {
+		return pipelineError(err, CodePipelineStageNotFound, CodePipelineStageCouldNotBeUpdated)
+	}
+	return nil
+}
+
+// UpdatePipelineStage calls Client.UpdatePipelineStage on the DefaultClient with a background context
+func UpdatePipelineStage(objectType PipelineObjectType, pipelineID string, stage *PipelineStage) error {
+	return DefaultClient().UpdatePipelineStage(context.Background(), objectType, pipelineID, stage)
+}
+
+// DeletePipelineStage deletes a stage from a pipeline. Hubspot will not delete a stage that still has deals or tickets
+// in it.
+//
+// API Doc: https://developers.hubspot.com/docs/api/crm/pipelines
+func (c *Client) DeletePipelineStage(ctx context.Context, objectType PipelineObjectType, pipelineID, stageID string) error {
+	if pipelineID == "" || stageID == "" {
+		return pipelineMissingDataError("the IDs of the pipeline and stage are required")
+	}
+	_, err := c.prepareCall(ctx, EndpointDeletePipelineStage, map[string]string{
+		":objectType": string(objectType),
+		":pipelineID": pipelineID,
+		":stageID":    stageID,
+	}, nil, nil)
+	if err != nil {
+		return pipelineError(err, CodePipelineStageNotFound, CodePipelineStageCouldNotBeDeleted)
+	}
+	return nil
+}
+
+// DeletePipelineStage calls Client.DeletePipelineStage on the DefaultClient with a background context
+func DeletePipelineStage(objectType PipelineObjectType, pipelineID, stageID string) error {
+	return DefaultClient().DeletePipelineStage(context.Background(), objectType, pipelineID, stageID)
+}
+
+// PipelineCache keeps the pipelines of one object type in memory, so stage labels can be turned in to IDs and stages
+// checked before a deal or ticket is written without asking Hubspot each time. It is safe to use from more than one
+// goroutine.
+//
+// The pipelines are fetched on first use and again once the TTL has passed. A lookup that finds nothing also fetches
+// them again, at most once, in case the pipelines have changed since they were cached.
+type PipelineCache struct {
+	client     *Client
+	objectType PipelineObjectType
+	ttl        time.Duration
+
+	mutex     sync.Mutex
+	pipelines []Pipeline
+	fetchedAt time.Time
+}
+
+// NewPipelineCache creates a cache of the pipelines for the object type. A TTL of 0 or less keeps the pipelines until
+// Refresh is called.
+func (c *Client) NewPipelineCache(objectType PipelineObjectType, ttl time.Duration) *PipelineCache {
+	return &PipelineCache{
+		client:     c,
+		objectType: objectType,
+		ttl:        ttl,
+	}
+}
+
+// NewPipelineCache calls Client.NewPipelineCache on the DefaultClient
+func NewPipelineCache(objectType PipelineObjectType, ttl time.Duration) *PipelineCache {
+	return DefaultClient().NewPipelineCache(objectType, ttl)
+}
+
+// Refresh fetches the pipelines from Hubspot now. Call it after changing the pipelines or stages.
+func (cache *PipelineCache) Refresh(ctx context.Context) error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.refresh(ctx)
+}
+
+// Pipelines returns the cached pipelines, fetching them if needed
+func (cache *PipelineCache) Pipelines(ctx context.Context) ([]Pipeline, error) {
+	var pipelines []Pipeline
+	err := cache.find(ctx, func(cached []Pipeline) bool {
+		pipelines = cached
+		return true
+	})
+	return pipelines, err
+}
+
+// PipelineID returns the ID of the pipeline with the label. Labels are matched ignoring case.
+func (cache *PipelineCache) PipelineID(ctx context.Context, label string) (string, error) {
+	id := ""
+	err := cache.find(ctx, func(pipelines []Pipeline) bool {
+		for _, pipeline := range pipelines {
+			if labelsMatch(pipeline.Label, label) {
+				id = pipeline.ID
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		return "", APIError{
+			HTTPCode:   http.StatusNotFound,
+			SystemCode: CodePipelineNotFound,
+			Message:    fmt.Sprintf("there is no %s pipeline labelled %q", cache.objectType, label),
+			Body:       nil,
+		}
+	}
+	return id, nil
+}
+
+// StageID returns the ID of the stage with the label in the pipeline. Labels are matched ignoring case, and the same
+// label can be used in more than one pipeline, which is why the pipeline ID is needed.
+func (cache *PipelineCache) StageID(ctx context.Context, pipelineID, label string) (string, error) {
+	pipelineFound := false
+	id := ""
+	err := cache.find(ctx, func(pipelines []Pipeline) bool {
+		pipelineFound = false
+		for _, pipeline := range pipelines {
+			if pipeline.ID != pipelineID {
+				continue
+			}
+			pipelineFound = true
+			for _, stage := range pipeline.Stages {
+				if labelsMatch(stage.Label, label) {
+					id = stage.ID
+					return true
+				}
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return "", err
+	}
+	if !pipelineFound {
+		return "", cache.pipelineNotFoundError(pipelineID)
+	}
+	if id == "" {
+		return "", APIError{
+			HTTPCode:   http.StatusNotFound,
+			SystemCode: CodePipelineStageNotFound,
+			Message:    fmt.Sprintf("there is no stage labelled %q in the %s pipeline %s", label, cache.objectType, pipelineID),
+			Body:       nil,
+		}
+	}
+	return id, nil
+}
+
+// ValidateStage checks that the stage is in the pipeline. When pipelineID is blank, as when only the stage of a deal is
+// being updated, it checks that the stage is in any of the pipelines.
+func (cache *PipelineCache) ValidateStage(ctx context.Context, pipelineID, stageID string) error {
+	pipelineFound := false
+	stagePipeline := ""
+	err := cache.find(ctx, func(pipelines []Pipeline) bool {
+		pipelineFound = pipelineID == ""
+		stagePipeline = ""
+		for _, pipeline := range pipelines {
+			if pipeline.ID == pipelineID {
+				pipelineFound = true
+			}
+			for _, stage := range pipeline.Stages {
+				if stage.ID == stageID {
+					stagePipeline = pipeline.ID
+					if pipelineID == "" || pipeline.ID == pipelineID {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+	if !pipelineFound {
+		return cache.pipelineNotFoundError(pipelineID)
+	}
+	if stagePipeline == "" {
+		return APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodePipelineStageNotFound,
+			Message:    fmt.Sprintf("there is no %s stage with the ID %q", cache.objectType, stageID),
+			Body:       nil,
+		}
+	}
+	if pipelineID != "" && stagePipeline != pipelineID {
+		return APIError{
+			HTTPCode:   http.StatusBadRequest,
+			SystemCode: CodePipelineStageNotInPipeline,
+			Message:    fmt.Sprintf("the stage %q is in the pipeline %s, not %s", stageID, stagePipeline, pipelineID),
+			Body:       nil,
+		}
+	}
+	return nil
+}
+
+// ValidateDeal checks that the deal's stage is in its pipeline before the deal is sent to Hubspot. A deal without a
+// DealStage is not changing stage, so it is always valid. The cache must be for PipelineObjectTypeDeals.
+func (cache *PipelineCache) ValidateDeal(ctx context.Context, deal *Deal) error {
+	if deal.DealStage == "" {
+		return nil
+	}
+	return cache.ValidateStage(ctx, deal.Pipeline, deal.DealStage)
+}
+
+// find runs the match against the cached pipelines, fetching them first if needed. If nothing matches and the pipelines
+// were not just fetched, they are fetched again and the match is tried once more.
+func (cache *PipelineCache) find(ctx context.Context, match func(pipelines []Pipeline) bool) error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	fetched := false
+	if cache.pipelines == nil || (cache.ttl > 0 && time.Since(cache.fetchedAt) > cache.ttl) {
+		if err := cache.refresh(ctx); err != nil {
+			return err
+		}
+		fetched = true
+	}
+	if match(cache.pipelines) || fetched {
+		return nil
+	}
+	if err := cache.refresh(ctx); err != nil {
+		return err
+	}
+	match(cache.pipelines)
+	return nil
+}
+
+// refresh fetches the pipelines. The mutex must be held.
+func (cache *PipelineCache) refresh(ctx context.Context) error {
+	pipelines, err := cache.client.ListPipelines(ctx, cache.objectType)
+	if err != nil {
+		return err
+	}
+	cache.pipelines = pipelines
+	cache.fetchedAt = time.Now()
+	return nil
+}
+
+func (cache *PipelineCache) pipelineNotFoundError(pipelineID string) APIError {
+	return APIError{
+		HTTPCode:   http.StatusNotFound,
+		SystemCode: CodePipelineNotFound,
+		Message:    fmt.Sprintf("there is no %s pipeline with the ID %q", cache.objectType, pipelineID),
+		Body:       nil,
+	}
+}
+
+// request builds the body to create or update the stage
+func (stage *PipelineStage) request() pipelineStageRequest {
+	return pipelineStageRequest{
+		Label:        stage.Label,
+		DisplayOrder: stage.DisplayOrder,
+		Metadata:     stage.Metadata,
+	}
+}
+
+// labelsMatch compares two labels the way a person would, ignoring case and surrounding spaces
+func labelsMatch(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// pipelineError sets the system code on an error from the pipelines API
+func pipelineError(err error, notFoundCode, failureCode string) error {
+	if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+		if apiErr.HTTPCode == 404 {
+			apiErr.SystemCode = notFoundCode
+			return apiErr
+		}
+		apiErr.SystemCode = failureCode
+		return apiErr
+	}
+	return err
+}
+
+// pipelineMissingDataError is returned when a pipeline or stage call is missing something it needs
+func pipelineMissingDataError(message string) APIError {
+	return APIError{
+		HTTPCode:   http.StatusBadRequest,
+		SystemCode: CodePipelineMissingData,
+		Message:    message,
+		Body:       nil,
+	}
+}
@@ -0,0 +1,231 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// pipelineV1 is a pipeline as the v1 CRM pipelines API sends and receives it, with an active flag in place of archived
+// and the times in milliseconds
+type pipelineV1 struct {
+	PipelineID   string            `json:"pipelineId,omitempty"`
+	Label        string            `json:"label"`
+	DisplayOrder int               `json:"displayOrder"`
+	Active       bool              `json:"active"`
+	Stages       []pipelineStageV1 `json:"stages"`
+	CreatedAt    *timestamp        `json:"createdAt,omitempty"`
+	UpdatedAt    *timestamp        `json:"updatedAt,omitempty"`
+}
+
+// pipelineStageV1 is a stage as the v1 CRM pipelines API sends and receives it
+type pipelineStageV1 struct {
+	StageID      string            `json:"stageId,omitempty"`
+	Label        string            `json:"label"`
+	DisplayOrder int               `json:"displayOrder"`
+	Active       bool              `json:"active"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
+
+// pipelineV1ListResponse is the pipelines as returned by the v1 CRM pipelines API
+type pipelineV1ListResponse struct {
+	Results []pipelineV1 `json:"results"`
+}
+
+// ListPipelinesV1 gets every active pipeline for the object type, along with their stages, from the v1 CRM pipelines
+// API. The pipelines are the same as those from ListPipelines; the v1 calls are kept for apps that have not moved to
+// the v3 API yet.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/pipelines/get_pipelines_for_object_type
+func (c *Client) ListPipelinesV1(ctx context.Context, objectType PipelineObjectType) ([]Pipeline, error) {
+	return c.listPipelinesV1(ctx, objectType, false)
+}
+
+// ListPipelinesV1 calls Client.ListPipelinesV1 on the DefaultClient with a background context
+func ListPipelinesV1(objectType PipelineObjectType) ([]Pipeline, error) {
+	return DefaultClient().ListPipelinesV1(context.Background(), objectType)
+}
+
+// GetPipelineV1 gets a single pipeline by its ID, along with its stages, from the v1 CRM pipelines API. The v1 API has
+// no call for a single pipeline; listing them all, including inactive ones, and picking it out is the only way it
+// offers to get one. Use GetPipeline to fetch just the one pipeline through the v3 API.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/pipelines/get_pipelines_for_object_type
+func (c *Client) GetPipelineV1(ctx context.Context, objectType PipelineObjectType, pipelineID string) (Pipeline, error) {
+	if pipelineID == "" {
+		return Pipeline{}, pipelineMissingDataError("the ID of the pipeline is required")
+	}
+	pipelines, err := c.listPipelinesV1(ctx, objectType, true)
+	if err != nil {
+		return Pipeline{}, err
+	}
+	for _, pipeline := range pipelines {
+		if pipeline.ID == pipelineID {
+			return pipeline, nil
+		}
+	}
+	return Pipeline{}, APIError{
+		HTTPCode:   http.StatusNotFound,
+		SystemCode: CodePipelineNotFound,
+		Message:    fmt.Sprintf("there is no %s pipeline with the ID %q", objectType, pipelineID),
+		Body:       nil,
+	}
+}
+
+// GetPipelineV1 calls Client.GetPipelineV1 on the DefaultClient with a background context
+func GetPipelineV1(objectType PipelineObjectType, pipelineID string) (Pipeline, error) {
+	return DefaultClient().GetPipelineV1(context.Background(), objectType, pipelineID)
+}
+
+// CreatePipelineV1 creates a new pipeline with its stages through the v1 CRM pipelines API. The pipeline is filled in
+// with what Hubspot returns, including the IDs of the pipeline and its stages.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/pipelines/create_new_pipeline
+func (c *Client) CreatePipelineV1(ctx context.Context, objectType PipelineObjectType, pipeline *Pipeline) error {
+	if pipeline.Label == "" || len(pipeline.Stages) == 0 {
+		return pipelineMissingDataError("a pipeline needs a label and at least one stage")
+	}
+	send, err := pipeline.requestV1()
+	if err != nil {
+		return err
+	}
+	result := pipelineV1{}
+	_, err = c.prepareCall(ctx, EndpointCreatePipelineV1, map[string]string{
+		":objectType": string(objectType),
+	}, send, &result)
+	if err != nil {
+		return pipelineError(err, CodePipelineNotFound, CodePipelineCouldNotBeCreated)
+	}
+	*pipeline = result.pipeline()
+	return nil
+}
+
+// CreatePipelineV1 calls Client.CreatePipelineV1 on the DefaultClient with a background context
+func CreatePipelineV1(objectType PipelineObjectType, pipeline *Pipeline) error {
+	return DefaultClient().CreatePipelineV1(context.Background(), objectType, pipeline)
+}
+
+// UpdatePipelineV1 replaces a pipeline and its stages through the v1 CRM pipelines API. Unlike UpdatePipeline, the
+// stages are sent too: stages with an ID are updated, stages without one are added and stages that are left out are
+// removed, so start from the pipeline as it was fetched.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/pipelines/update_pipeline
+func (c *Client) UpdatePipelineV1(ctx context.Context, objectType PipelineObjectType, pipeline *Pipeline) error {
+	if pipeline.ID == "" || pipeline.Label == "" || len(pipeline.Stages) == 0 {
+		return pipelineMissingDataError("the ID and label of the pipeline and at least one stage are required")
+	}
+	send, err := pipeline.requestV1()
+	if err != nil {
+		return err
+	}
+	result := pipelineV1{}
+	_, err = c.prepareCall(ctx, EndpointUpdatePipelineV1, map[string]string{
+		":objectType": string(objectType),
+		":pipelineID": pipeline.ID,
+	}, send, &result)
+	if err != nil {
+		return pipelineError(err, CodePipelineNotFound, CodePipelineCouldNotBeUpdated)
+	}
+	*pipeline = result.pipeline()
+	return nil
+}
+
+// UpdatePipelineV1 calls Client.UpdatePipelineV1 on the DefaultClient with a background context
+func UpdatePipelineV1(objectType PipelineObjectType, pipeline *Pipeline) error {
+	return DefaultClient().UpdatePipelineV1(context.Background(), objectType, pipeline)
+}
+
+// DeletePipelineV1 deletes a pipeline by its ID through the v1 CRM pipelines API
+//
+// API Doc: https://developers.hubspot.com/docs/methods/pipelines/delete_pipeline
+func (c *Client) DeletePipelineV1(ctx context.Context, objectType PipelineObjectType, pipelineID string) error {
+	if pipelineID == "" {
+		return pipelineMissingDataError("the ID of the pipeline is required")
+	}
+	_, err := c.prepareCall(ctx, EndpointDeletePipelineV1, map[string]string{
+		":objectType": string(objectType),
+		":pipelineID": pipelineID,
+	}, nil, nil)
+	if err != nil {
+		return pipelineError(err, CodePipelineNotFound, CodePipelineCouldNotBeDeleted)
+	}
+	return nil
+}
+
+// DeletePipelineV1 calls Client.DeletePipelineV1 on the DefaultClient with a background context
+func DeletePipelineV1(objectType PipelineObjectType, pipelineID string) error {
+	return DefaultClient().DeletePipelineV1(context.Background(), objectType, pipelineID)
+}
+
+// listPipelinesV1 gets the pipelines for the object type, leaving out the inactive ones unless asked for them
+func (c *Client) listPipelinesV1(ctx context.Context, objectType PipelineObjectType, includeInactive bool) ([]Pipeline, error) {
+	inactive := "EXCLUDE_DELETED"
+	if includeInactive {
+		inactive = "INCLUDE_DELETED"
+	}
+	result := pipelineV1ListResponse{}
+	_, err := c.prepareCall(ctx, EndpointListPipelinesV1, map[string]string{
+		":objectType": string(objectType),
+	}, map[string]string{
+		"includeInactive": inactive,
+	}, &result)
+	if err != nil {
+		return []Pipeline{}, pipelineError(err, CodePipelineNotFound, CodePipelineCouldNotBeListed)
+	}
+	pipelines := make([]Pipeline, len(result.Results))
+	for i := range result.Results {
+		pipelines[i] = result.Results[i].pipeline()
+	}
+	return pipelines, nil
+}
+
+// requestV1 builds the body to create or replace the pipeline through the v1 API
+func (pipeline *Pipeline) requestV1() (pipelineV1, error) {
+	send := pipelineV1{
+		PipelineID:   pipeline.ID,
+		Label:        pipeline.Label,
+		DisplayOrder: pipeline.DisplayOrder,
+		Active:       !pipeline.Archived,
+		Stages:       make([]pipelineStageV1, len(pipeline.Stages)),
+	}
+	for i, stage := range pipeline.Stages {
+		if stage.Label == "" {
+			return send, pipelineMissingDataError("every stage needs a label")
+		}
+		send.Stages[i] = pipelineStageV1{
+			StageID:      stage.ID,
+			Label:        stage.Label,
+			DisplayOrder: stage.DisplayOrder,
+			Active:       !stage.Archived,
+			Metadata:     stage.Metadata,
+		}
+	}
+	return send, nil
+}
+
+// pipeline converts a v1 pipeline to the Pipeline the rest of the package uses
+func (response pipelineV1) pipeline() Pipeline {
+	pipeline := Pipeline{
+		ID:           response.PipelineID,
+		Label:        response.Label,
+		DisplayOrder: response.DisplayOrder,
+		Stages:       make([]PipelineStage, len(response.Stages)),
+		Archived:     !response.Active,
+	}
+	if response.CreatedAt != nil {
+		pipeline.CreatedAt = response.CreatedAt.Time
+	}
+	if response.UpdatedAt != nil {
+		pipeline.UpdatedAt = response.UpdatedAt.Time
+	}
+	for i, stage := range response.Stages {
+		pipeline.Stages[i] = PipelineStage{
+			ID:           stage.StageID,
+			Label:        stage.Label,
+			DisplayOrder: stage.DisplayOrder,
+			Metadata:     stage.Metadata,
+			Archived:     !stage.Active,
+		}
+	}
+	return pipeline
+}
@@ -0,0 +1,112 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPipelinesV1Response = `{"results": [
+	{"pipelineId": "default", "label": "Sales Pipeline", "displayOrder": 0, "active": true, "createdAt": 1577836800000, "stages": [
+		{"stageId": "appointmentscheduled", "label": "Appointment Scheduled", "displayOrder": 0, "active": true, "metadata": {"probability": "0.2"}},
+		{"stageId": "closedwon", "label": "Closed Won", "displayOrder": 1, "active": true, "metadata": {"probability": "1.0"}}
+	]},
+	{"pipelineId": "renewals", "label": "Renewals", "displayOrder": 1, "active": false, "stages": [
+		{"stageId": "renewalopen", "label": "Open", "displayOrder": 0, "active": true}
+	]}
+]}`
+
+func TestPipelineV1Calls(t *testing.T) {
+	status := http.StatusOK
+	var method, path, inactive string
+	var sent map[string]interface{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		inactive = r.URL.Query().Get("includeInactive")
+		sent = nil
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, testPipelinesV1Response)
+		case http.MethodDelete:
+		default:
+			fmt.Fprint(w, `{"pipelineId": "renewals", "label": "Renewals", "displayOrder": 1, "active": true, "stages": [
+				{"stageId": "renewalopen", "label": "Open", "displayOrder": 0, "active": true, "metadata": {"probability": "0.5"}}
+			]}`)
+		}
+	})
+	defer server.Close()
+
+	pipelines, err := client.ListPipelinesV1(context.Background(), PipelineObjectTypeDeals)
+	require.Nil(t, err)
+	assert.Equal(t, "/crm-pipelines/v1/pipelines/deals", path)
+	assert.Equal(t, "EXCLUDE_DELETED", inactive)
+	require.Len(t, pipelines, 2)
+	assert.Equal(t, "default", pipelines[0].ID)
+	assert.Equal(t, "closedwon", pipelines[0].Stages[1].ID)
+	assert.Equal(t, "0.2", pipelines[0].Stages[0].Metadata["probability"])
+	assert.Equal(t, 2020, pipelines[0].CreatedAt.Year())
+	assert.False(t, pipelines[0].Archived)
+	assert.True(t, pipelines[1].Archived)
+
+	// there is no v1 call for a single pipeline, so it is picked out of the listing, inactive ones included
+	pipeline, err := client.GetPipelineV1(context.Background(), PipelineObjectTypeDeals, "renewals")
+	require.Nil(t, err)
+	assert.Equal(t, "INCLUDE_DELETED", inactive)
+	assert.Equal(t, "Renewals", pipeline.Label)
+	_, err = client.GetPipelineV1(context.Background(), PipelineObjectTypeDeals, "missing")
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineNotFound, err.(APIError).SystemCode)
+
+	err = client.CreatePipelineV1(context.Background(), PipelineObjectTypeDeals, &Pipeline{Label: "Renewals"})
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineMissingData, err.(APIError).SystemCode)
+	pipeline = Pipeline{Label: "Renewals", DisplayOrder: 1, Stages: []PipelineStage{
+		{Label: "Open", Metadata: map[string]string{"probability": "0.5"}},
+	}}
+	require.Nil(t, client.CreatePipelineV1(context.Background(), PipelineObjectTypeDeals, &pipeline))
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/crm-pipelines/v1/pipelines/deals", path)
+	assert.Equal(t, map[string]interface{}{
+		"label":        "Renewals",
+		"displayOrder": float64(1),
+		"active":       true,
+		"stages": []interface{}{
+			map[string]interface{}{"label": "Open", "displayOrder": float64(0), "active": true, "metadata": map[string]interface{}{"probability": "0.5"}},
+		},
+	}, sent)
+	assert.Equal(t, "renewals", pipeline.ID)
+	assert.Equal(t, "renewalopen", pipeline.Stages[0].ID)
+
+	pipeline.Stages = append(pipeline.Stages, PipelineStage{Label: "Renewed", DisplayOrder: 1})
+	require.Nil(t, client.UpdatePipelineV1(context.Background(), PipelineObjectTypeDeals, &pipeline))
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/crm-pipelines/v1/pipelines/deals/renewals", path)
+	assert.Equal(t, "renewals", sent["pipelineId"])
+	require.Len(t, sent["stages"], 2)
+	assert.Equal(t, "renewalopen", sent["stages"].([]interface{})[0].(map[string]interface{})["stageId"])
+	assert.NotContains(t, sent["stages"].([]interface{})[1], "stageId")
+
+	status = http.StatusNoContent
+	require.Nil(t, client.DeletePipelineV1(context.Background(), PipelineObjectTypeDeals, "renewals"))
+	assert.Equal(t, http.MethodDelete, method)
+	assert.Equal(t, "/crm-pipelines/v1/pipelines/deals/renewals", path)
+
+	status = http.StatusNotFound
+	err = client.DeletePipelineV1(context.Background(), PipelineObjectTypeTickets, "missing")
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineNotFound, err.(APIError).SystemCode)
+
+	status = http.StatusBadRequest
+	err = client.UpdatePipelineV1(context.Background(), PipelineObjectTypeDeals, &pipeline)
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineCouldNotBeUpdated, err.(APIError).SystemCode)
+}
@@ -0,0 +1,178 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPipelinesResponse = `{"results": [
+	{"id": "default", "label": "Sales Pipeline", "displayOrder": 0, "archived": false, "createdAt": "2020-01-01T00:00:00Z", "stages": [
+		{"id": "appointmentscheduled", "label": "Appointment Scheduled", "displayOrder": 0, "metadata": {"probability": "0.2"}},
+		{"id": "closedwon", "label": "Closed Won", "displayOrder": 1, "metadata": {"probability": "1.0", "isClosed": "true"}}
+	]},
+	{"id": "renewals", "label": "Renewals", "displayOrder": 1, "stages": [
+		{"id": "renewalopen", "label": "Open", "displayOrder": 0, "metadata": {"probability": "0.5"}}
+	]}
+]}`
+
+func TestPipelineCalls(t *testing.T) {
+	status := http.StatusOK
+	var method, path string
+	var sent map[string]interface{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		sent = nil
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		switch {
+		case r.URL.Path == "/crm/v3/pipelines/deals" && r.Method == http.MethodGet:
+			assert.Equal(t, "false", r.URL.Query().Get("archived"))
+			fmt.Fprint(w, testPipelinesResponse)
+		case r.URL.Path == "/crm/v3/pipelines/deals/renewals/stages" && r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"results": [{"id": "renewalopen", "label": "Open"}]}`)
+		case r.Method == http.MethodDelete:
+		case r.URL.Path == "/crm/v3/pipelines/deals/renewals/stages" || r.URL.Path == "/crm/v3/pipelines/deals/renewals/stages/renewalwon":
+			fmt.Fprint(w, `{"id": "renewalwon", "label": "Renewed", "displayOrder": 1, "metadata": {"probability": "1.0"}}`)
+		default:
+			fmt.Fprint(w, `{"id": "renewals", "label": "Renewals", "displayOrder": 1, "stages": [{"id": "renewalopen", "label": "Open"}]}`)
+		}
+	})
+	defer server.Close()
+
+	pipelines, err := client.ListPipelines(context.Background(), PipelineObjectTypeDeals)
+	require.Nil(t, err)
+	require.Len(t, pipelines, 2)
+	assert.Equal(t, "closedwon", pipelines[0].Stages[1].ID)
+	assert.Equal(t, "0.2", pipelines[0].Stages[0].Metadata["probability"])
+	assert.Equal(t, 2020, pipelines[0].CreatedAt.Year())
+
+	pipeline, err := client.GetPipeline(context.Background(), PipelineObjectTypeDeals, "renewals")
+	require.Nil(t, err)
+	assert.Equal(t, "/crm/v3/pipelines/deals/renewals", path)
+	assert.Equal(t, "Renewals", pipeline.Label)
+
+	err = client.CreatePipeline(context.Background(), PipelineObjectTypeDeals, &Pipeline{Label: "Renewals"})
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineMissingData, err.(APIError).SystemCode)
+	pipeline = Pipeline{Label: "Renewals", DisplayOrder: 1, Stages: []PipelineStage{
+		{Label: "Open", Metadata: map[string]string{"probability": "0.5"}},
+	}}
+	require.Nil(t, client.CreatePipeline(context.Background(), PipelineObjectTypeDeals, &pipeline))
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/crm/v3/pipelines/deals", path)
+	assert.Equal(t, map[string]interface{}{
+		"label":        "Renewals",
+		"displayOrder": float64(1),
+		"stages": []interface{}{
+			map[string]interface{}{"label": "Open", "displayOrder": float64(0), "metadata": map[string]interface{}{"probability": "0.5"}},
+		},
+	}, sent)
+	assert.Equal(t, "renewals", pipeline.ID)
+	assert.Equal(t, "renewalopen", pipeline.Stages[0].ID)
+
+	pipeline.Label = "Renewals"
+	require.Nil(t, client.UpdatePipeline(context.Background(), PipelineObjectTypeDeals, &pipeline))
+	assert.Equal(t, http.MethodPatch, method)
+	assert.Equal(t, "/crm/v3/pipelines/deals/renewals", path)
+	assert.Equal(t, map[string]interface{}{"label": "Renewals", "displayOrder": float64(1)}, sent)
+
+	stages, err := client.ListPipelineStages(context.Background(), PipelineObjectTypeDeals, "renewals")
+	require.Nil(t, err)
+	assert.Equal(t, "Open", stages[0].Label)
+
+	stage := PipelineStage{Label: "Renewed", DisplayOrder: 1, Metadata: map[string]string{"probability": "1.0"}}
+	require.Nil(t, client.CreatePipelineStage(context.Background(), PipelineObjectTypeDeals, "renewals", &stage))
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "renewalwon", stage.ID)
+	require.Nil(t, client.UpdatePipelineStage(context.Background(), PipelineObjectTypeDeals, "renewals", &stage))
+	assert.Equal(t, http.MethodPatch, method)
+	assert.Equal(t, "/crm/v3/pipelines/deals/renewals/stages/renewalwon", path)
+	stage, err = client.GetPipelineStage(context.Background(), PipelineObjectTypeDeals, "renewals", "renewalwon")
+	require.Nil(t, err)
+	assert.Equal(t, "Renewed", stage.Label)
+
+	status = http.StatusNoContent
+	require.Nil(t, client.DeletePipelineStage(context.Background(), PipelineObjectTypeDeals, "renewals", "renewalwon"))
+	assert.Equal(t, "/crm/v3/pipelines/deals/renewals/stages/renewalwon", path)
+	require.Nil(t, client.DeletePipeline(context.Background(), PipelineObjectTypeDeals, "renewals"))
+	assert.Equal(t, "/crm/v3/pipelines/deals/renewals", path)
+
+	status = http.StatusNotFound
+	_, err = client.GetPipeline(context.Background(), PipelineObjectTypeTickets, "missing")
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineNotFound, err.(APIError).SystemCode)
+	err = client.DeletePipelineStage(context.Background(), PipelineObjectTypeDeals, "renewals", "missing")
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineStageNotFound, err.(APIError).SystemCode)
+
+	status = http.StatusBadRequest
+	err = client.UpdatePipelineStage(context.Background(), PipelineObjectTypeDeals, "renewals", &stage)
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineStageCouldNotBeUpdated, err.(APIError).SystemCode)
+}
+
+func TestPipelineCache(t *testing.T) {
+	fetches := 0
+	response := testPipelinesResponse
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, response)
+	})
+	defer server.Close()
+
+	cache := client.NewPipelineCache(PipelineObjectTypeDeals, time.Hour)
+	id, err := cache.PipelineID(context.Background(), "sales pipeline")
+	require.Nil(t, err)
+	assert.Equal(t, "default", id)
+	id, err = cache.StageID(context.Background(), "default", " closed won ")
+	require.Nil(t, err)
+	assert.Equal(t, "closedwon", id)
+	assert.Equal(t, 1, fetches)
+
+	require.Nil(t, cache.ValidateStage(context.Background(), "default", "closedwon"))
+	require.Nil(t, cache.ValidateStage(context.Background(), "", "renewalopen"))
+	require.Nil(t, cache.ValidateDeal(context.Background(), &Deal{ID: 1, Name: "Renewal"}))
+	assert.Equal(t, 1, fetches)
+
+	err = cache.ValidateDeal(context.Background(), &Deal{Pipeline: "default", DealStage: "renewalopen"})
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineStageNotInPipeline, err.(APIError).SystemCode)
+	// a stage that doesn't match fetches the pipelines again in case they changed
+	assert.Equal(t, 2, fetches)
+
+	response = `{"results": [{"id": "default", "label": "Sales Pipeline", "stages": [{"id": "negotiation", "label": "Negotiation"}]}]}`
+	id, err = cache.StageID(context.Background(), "default", "Negotiation")
+	require.Nil(t, err)
+	assert.Equal(t, "negotiation", id)
+	assert.Equal(t, 3, fetches)
+
+	_, err = cache.StageID(context.Background(), "default", "Lost")
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineStageNotFound, err.(APIError).SystemCode)
+	_, err = cache.StageID(context.Background(), "missing", "Lost")
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineNotFound, err.(APIError).SystemCode)
+	err = cache.ValidateStage(context.Background(), "default", "closedwon")
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineStageNotFound, err.(APIError).SystemCode)
+	_, err = cache.PipelineID(context.Background(), "Renewals")
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineNotFound, err.(APIError).SystemCode)
+
+	fetches = 0
+	require.Nil(t, cache.Refresh(context.Background()))
+	pipelines, err := cache.Pipelines(context.Background())
+	require.Nil(t, err)
+	require.Len(t, pipelines, 1)
+	assert.Equal(t, 1, fetches)
+}
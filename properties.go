@@ -0,0 +1,448 @@
+package hubspot
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PropertyValue is the value of a Hubspot property. Hubspot sends every property as a string, except when it doesn't:
+// unset properties can come back as null, and some properties come back as numbers or booleans. PropertyValue accepts
+// all of those, keeping the text of numbers and booleans and treating null as empty.
+type PropertyValue string
+
+// UnmarshalJSON decodes a string, number, boolean or null
+func (v *PropertyValue) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		*v = ""
+		return nil
+	}
+	switch data[0] {
+	case '"':
+		s := ""
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*v = PropertyValue(s)
+		return nil
+	case 't', 'f', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		// validate it before keeping the raw text
+		var scalar interface{}
+		if err := json.Unmarshal(data, &scalar); err != nil {
+			return err
+		}
+		*v = PropertyValue(data)
+		return nil
+	}
+	return fmt.Errorf("hubspot: a property value must be a string, number, boolean or null, not %s", string(data))
+}
+
+// String returns the value as a string
+func (v PropertyValue) String() string {
+	return string(v)
+}
+
+// timestamp is a time Hubspot sends as milliseconds since the epoch. Like property values, it can come as a number, a
+// string or null, and 0 and null both mean there is no time.
+type timestamp struct {
+	time.Time
+}
+
+// UnmarshalJSON decodes a timestamp in any of the forms Hubspot uses
+func (t *timestamp) UnmarshalJSON(data []byte) error {
+	var value PropertyValue
+	if err := value.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	if value == "" || value == "0" {
+		t.Time = time.Time{}
+		return nil
+	}
+	parsed, err := parsePropertyTime(value.String())
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// propertyTag is the struct tag used to map a field to a Hubspot property
+const propertyTag = "hubspot"
+
+// multiSelectSeparator separates the options of a multi-select property
+const multiSelectSeparator = ";"
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// property is a single named property value, kept in the order the fields are declared
+type property struct {
+	Name  string
+	Value string
+}
+
+// MarshalProperties converts a struct, or a pointer to one, in to Hubspot property values using its `hubspot` struct
+// tags. A field tagged `hubspot:"lifecyclestage"` is sent as the lifecyclestage property; fields without a tag, or
+// tagged `hubspot:"-"`, are skipped, and embedded structs are included as if their fields were declared in place.
+//
+// Adding omitempty, as in `hubspot:"phone,omitempty"`, leaves the property out when the field is the zero value. A nil
+// pointer field is always left out, while a pointer to a zero value is always sent, which is how a property can be
+// cleared. Supported field types are strings and types based on them (such as enums), integers, floats, bools,
+// time.Time (sent as milliseconds since the epoch), slices of any of those for multi-select properties (joined with
+// ";") and anything implementing encoding.TextMarshaler.
+func MarshalProperties(v interface{}) (map[string]string, error) {
+	props, err := marshalPropertyList(v)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(props))
+	for _, p := range props {
+		values[p.Name] = p.Value
+	}
+	return values, nil
+}
+
+// UnmarshalProperties fills in a struct from Hubspot property values using its `hubspot` struct tags. It is the reverse
+// of MarshalProperties, and v must be a pointer to a struct. Properties without a matching field are ignored, fields
+// without a matching property are left alone, and empty values set the field to its zero value. Times are accepted as
+// milliseconds since the epoch, RFC 3339 or a plain date, which covers how the different Hubspot APIs send them.
+func UnmarshalProperties(values map[string]string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return propertyError("", fmt.Sprintf("UnmarshalProperties needs a pointer to a struct, not %T", v))
+	}
+	return unmarshalStruct(values, rv.Elem())
+}
+
+// marshalPropertyList converts the struct in to properties in the order its fields are declared
+func marshalPropertyList(v interface{}) ([]property, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return []property{}, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, propertyError("", fmt.Sprintf("MarshalProperties needs a struct, not %T", v))
+	}
+	props := []property{}
+	return marshalStruct(rv, props)
+}
+
+func marshalStruct(rv reflect.Value, props []property) ([]property, error) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, omitEmpty, tagged := parsePropertyTag(field)
+		if !tagged {
+			if field.Anonymous && indirectType(field.Type).Kind() == reflect.Struct && indirectType(field.Type) != timeType {
+				embedded := rv.Field(i)
+				if embedded.Kind() == reflect.Ptr {
+					if embedded.IsNil() {
+						continue
+					}
+					embedded = embedded.Elem()
+				}
+				var err error
+				if props, err = marshalStruct(embedded, props); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			// unexported fields can't be read
+			continue
+		}
+
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+			omitEmpty = false
+		}
+		if omitEmpty && isEmptyProperty(fv) {
+			continue
+		}
+		value, err := formatProperty(fv)
+		if err != nil {
+			return nil, propertyError(name, err.Error())
+		}
+		props = append(props, property{Name: name, Value: value})
+	}
+	return props, nil
+}
+
+func unmarshalStruct(values map[string]string, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, _, tagged := parsePropertyTag(field)
+		if !tagged {
+			if field.Anonymous && indirectType(field.Type).Kind() == reflect.Struct && indirectType(field.Type) != timeType {
+				embedded := rv.Field(i)
+				if embedded.Kind() == reflect.Ptr {
+					if embedded.IsNil() {
+						if !embedded.CanSet() {
+							continue
+						}
+						embedded.Set(reflect.New(field.Type.Elem()))
+					}
+					embedded = embedded.Elem()
+				}
+				if err := unmarshalStruct(values, embedded); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		value, found := values[name]
+		if !found {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if value == "" {
+			fv.Set(reflect.Zero(field.Type))
+			continue
+		}
+		if fv.Kind() == reflect.Ptr {
+			target := reflect.New(field.Type.Elem())
+			if err := parseProperty(value, target.Elem()); err != nil {
+				return propertyError(name, err.Error())
+			}
+			fv.Set(target)
+			continue
+		}
+		if err := parseProperty(value, fv); err != nil {
+			return propertyError(name, err.Error())
+		}
+	}
+	return nil
+}
+
+// clearProperties sets each of the named properties to a blank value, which is how Hubspot clears a property. A property
+// that is already in the list is replaced rather than sent twice.
+func clearProperties(props []property, names []string) []property {
+	if len(names) == 0 {
+		return props
+	}
+	toClear := make(map[string]bool, len(names))
+	for _, name := range names {
+		toClear[name] = true
+	}
+	kept := make([]property, 0, len(props)+len(names))
+	for _, p := range props {
+		if !toClear[p.Name] {
+			kept = append(kept, p)
+		}
+	}
+	for _, name := range names {
+		if toClear[name] {
+			kept = append(kept, property{Name: name, Value: ""})
+			// only clear each property once, even if it is listed twice
+			toClear[name] = false
+		}
+	}
+	return kept
+}
+
+// parsePropertyTag reads the property name and options from a field's tag
+func parsePropertyTag(field reflect.StructField) (name string, omitEmpty bool, tagged bool) {
+	tag, found := field.Tag.Lookup(propertyTag)
+	if !found || tag == "-" {
+		return "", false, false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name, omitEmpty, true
+}
+
+// formatProperty converts a field's value to the text Hubspot expects
+func formatProperty(fv reflect.Value) (string, error) {
+	if fv.Type() == timeType {
+		t := fv.Interface().(time.Time)
+		if t.IsZero() {
+			return "", nil
+		}
+		return strconv.FormatInt(toMilliseconds(t), 10), nil
+	}
+	if fv.Type().Implements(textMarshalerType) {
+		text, err := fv.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(fv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(fv.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(fv.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(fv.Float(), 'f', -1, 64), nil
+	case reflect.Slice:
+		options := make([]string, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			if fv.Index(i).Kind() == reflect.Slice {
+				return "", fmt.Errorf("multi-select properties cannot be nested")
+			}
+			option, err := formatProperty(fv.Index(i))
+			if err != nil {
+				return "", err
+			}
+			options[i] = option
+		}
+		return strings.Join(options, multiSelectSeparator), nil
+	}
+	return "", fmt.Errorf("fields of type %s cannot be converted to a property", fv.Type())
+}
+
+// parseProperty sets the field from the text Hubspot sent
+func parseProperty(value string, fv reflect.Value) error {
+	if fv.Type() == timeType {
+		t, err := parsePropertyTime(value)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if reflect.PtrTo(fv.Type()).Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			// Hubspot stores numbers as decimals, so whole numbers can come back as 42.0
+			f, floatErr := strconv.ParseFloat(value, 64)
+			if floatErr != nil || f != float64(int64(f)) {
+				return err
+			}
+			i = int64(f)
+		}
+		fv.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+		return nil
+	case reflect.Slice:
+		options := strings.Split(value, multiSelectSeparator)
+		slice := reflect.MakeSlice(fv.Type(), 0, len(options))
+		for _, option := range options {
+			if option == "" {
+				continue
+			}
+			element := reflect.New(fv.Type().Elem()).Elem()
+			if err := parseProperty(option, element); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, element)
+		}
+		fv.Set(slice)
+		return nil
+	}
+	return fmt.Errorf("fields of type %s cannot be set from a property", fv.Type())
+}
+
+// parsePropertyTime accepts the formats Hubspot uses for dates and times
+func parsePropertyTime(value string) (time.Time, error) {
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(0, millis*int64(time.Millisecond)).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// isEmptyProperty reports whether the value is the zero value for omitempty
+func isEmptyProperty(fv reflect.Value) bool {
+	if fv.Type() == timeType {
+		return fv.Interface().(time.Time).IsZero()
+	}
+	switch fv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return fv.Len() == 0
+	case reflect.Bool:
+		return !fv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return fv.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return fv.IsNil()
+	}
+	return false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// propertyError is returned when a field and a property can't be converted between each other
+func propertyError(name, message string) APIError {
+	if name != "" {
+		message = fmt.Sprintf("property %s: %s", name, message)
+	}
+	return APIError{
+		HTTPCode:   http.StatusBadRequest,
+		SystemCode: CodePropertyCouldNotBeConverted,
+		Message:    message,
+		Body:       nil,
+	}
+}
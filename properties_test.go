@@ -0,0 +1,141 @@
+package hubspot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLifecycleStage string
+
+const testLifecycleCustomer testLifecycleStage = "customer"
+
+type testPropertyBase struct {
+	Email string `hubspot:"email"`
+}
+
+type testPropertyStruct struct {
+	testPropertyBase
+	ID         int64                `hubspot:"-"`
+	Ignored    string               // no tag, so it is not a property
+	Name       string               `hubspot:"firstname,omitempty"`
+	Dogs       int                  `hubspot:"dog_count"`
+	Score      float64              `hubspot:"score,omitempty"`
+	Active     bool                 `hubspot:"active"`
+	RenewsOn   time.Time            `hubspot:"renews_on,omitempty"`
+	Stage      testLifecycleStage   `hubspot:"lifecyclestage,omitempty"`
+	Interests  []string             `hubspot:"interests,omitempty"`
+	Stages     []testLifecycleStage `hubspot:"past_stages,omitempty"`
+	Phone      *string              `hubspot:"phone,omitempty"`
+	Employees  *int                 `hubspot:"employees,omitempty"`
+	unexported string               `hubspot:"unexported"`
+}
+
+func TestMarshalProperties(t *testing.T) {
+	empty := ""
+	input := testPropertyStruct{
+		testPropertyBase: testPropertyBase{Email: "test@test.com"},
+		ID:               42,
+		Ignored:          "ignored",
+		Dogs:             2,
+		Score:            1.5,
+		RenewsOn:         time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Stage:            testLifecycleCustomer,
+		Interests:        []string{"walks", "treats"},
+		Stages:           []testLifecycleStage{"lead", testLifecycleCustomer},
+		// a pointer to an empty value is sent, even with omitempty, so the property can be cleared
+		Phone: &empty,
+	}
+	props, err := MarshalProperties(&input)
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"email":          "test@test.com",
+		"dog_count":      "2",
+		"score":          "1.5",
+		"active":         "false",
+		"renews_on":      "1577836800000",
+		"lifecyclestage": "customer",
+		"interests":      "walks;treats",
+		"past_stages":    "lead;customer",
+		"phone":          "",
+	}, props)
+
+	_, err = MarshalProperties("not a struct")
+	require.NotNil(t, err)
+	assert.Equal(t, CodePropertyCouldNotBeConverted, err.(APIError).SystemCode)
+
+	_, err = MarshalProperties(struct {
+		Bad map[string]string `hubspot:"bad"`
+	}{Bad: map[string]string{}})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "property bad")
+}
+
+func TestUnmarshalProperties(t *testing.T) {
+	output := testPropertyStruct{Name: "Old"}
+	err := UnmarshalProperties(map[string]string{
+		"email":          "test@test.com",
+		"firstname":      "",
+		"dog_count":      "3.0",
+		"score":          "2.25",
+		"active":         "true",
+		"renews_on":      "1577836800000",
+		"lifecyclestage": "customer",
+		"interests":      "walks;treats",
+		"past_stages":    "lead;customer",
+		"phone":          "555-1234",
+		"employees":      "10",
+		"unknown":        "ignored",
+		"unexported":     "ignored",
+	}, &output)
+	require.Nil(t, err)
+	assert.Equal(t, "test@test.com", output.Email)
+	assert.Equal(t, "", output.Name)
+	assert.Equal(t, 3, output.Dogs)
+	assert.Equal(t, 2.25, output.Score)
+	assert.True(t, output.Active)
+	assert.True(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Equal(output.RenewsOn))
+	assert.Equal(t, testLifecycleCustomer, output.Stage)
+	assert.Equal(t, []string{"walks", "treats"}, output.Interests)
+	assert.Equal(t, []testLifecycleStage{"lead", testLifecycleCustomer}, output.Stages)
+	require.NotNil(t, output.Phone)
+	assert.Equal(t, "555-1234", *output.Phone)
+	require.NotNil(t, output.Employees)
+	assert.Equal(t, 10, *output.Employees)
+	assert.Equal(t, "", output.unexported)
+
+	// the v3 APIs send times as RFC 3339, and date properties can be plain dates
+	err = UnmarshalProperties(map[string]string{"renews_on": "2020-01-02T03:04:05.678Z"}, &output)
+	require.Nil(t, err)
+	assert.Equal(t, 3, output.RenewsOn.Hour())
+	err = UnmarshalProperties(map[string]string{"renews_on": "2020-01-02"}, &output)
+	require.Nil(t, err)
+	assert.Equal(t, 2, output.RenewsOn.Day())
+
+	err = UnmarshalProperties(map[string]string{"dog_count": "lots"}, &output)
+	require.NotNil(t, err)
+	assert.Equal(t, CodePropertyCouldNotBeConverted, err.(APIError).SystemCode)
+	assert.Contains(t, err.Error(), "dog_count")
+	err = UnmarshalProperties(map[string]string{}, output)
+	require.NotNil(t, err)
+}
+
+func TestContactPropertiesFromStruct(t *testing.T) {
+	contact := Contact{VID: 42, Email: "test@test.com", FirstName: "Test"}
+	err := contact.SetProperties(struct {
+		DogCount int      `hubspot:"dog_count"`
+		Toys     []string `hubspot:"toys"`
+	}{DogCount: 2, Toys: []string{"ball", "rope"}})
+	require.Nil(t, err)
+
+	props := contact.convertContactToProperties()
+	// the VID and the additional properties pointer are not properties themselves
+	assert.Equal(t, []map[string]string{
+		{"property": "email", "value": "test@test.com"},
+		{"property": "firstname", "value": "Test"},
+		{"property": "dog_count", "value": "2"},
+		{"property": "toys", "value": "ball;rope"},
+	}, props)
+}
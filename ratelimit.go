@@ -0,0 +1,174 @@
+package hubspot
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AuthMode is the way a call authenticates with Hubspot. Hubspot applies separate rate limits to calls made with an API
+// key and calls made by an oAuth app, so each mode gets its own limiter.
+type AuthMode string
+
+// The supported authentication modes
+const (
+	AuthModeAPIKey AuthMode = "apikey"
+	AuthModeOAuth  AuthMode = "oauth"
+)
+
+// RateLimit is the number of requests that may be made in a rolling interval. A RateLimit with zero Requests disables
+// client-side limiting.
+type RateLimit struct {
+	Requests int
+	Interval time.Duration
+}
+
+// RateLimitStatus is a snapshot of the budget a client has left
+type RateLimitStatus struct {
+	// Remaining is the number of calls that can be made right now without waiting
+	Remaining int
+	// IntervalRemaining is the remaining budget for the current interval as last reported by Hubspot, or -1 if unknown
+	IntervalRemaining int
+	// DailyRemaining is the remaining budget for the day as last reported by Hubspot, or -1 if unknown
+	DailyRemaining int
+}
+
+// DefaultRateLimit returns the limit Hubspot applies on its base tiers, which is 100 requests every 10 seconds. The
+// limit is the same for API keys and oAuth apps, but each mode still gets its own bucket, and APIKeyRateLimit and
+// OAuthRateLimit can raise either one for accounts that allow more.
+func DefaultRateLimit() RateLimit {
+	return RateLimit{
+		Requests: 100,
+		Interval: 10 * time.Second,
+	}
+}
+
+// rateLimiter is a token bucket shared by every goroutine using a client. It refills continuously at the configured
+// rate and adapts to the remaining budget Hubspot reports in the response headers.
+type rateLimiter struct {
+	mutex             sync.Mutex
+	limit             RateLimit
+	tokens            float64
+	lastRefill        time.Time
+	intervalRemaining int
+	dailyRemaining    int
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	return &rateLimiter{
+		limit:             limit,
+		tokens:            float64(limit.Requests),
+		lastRefill:        time.Now(),
+		intervalRemaining: -1,
+		dailyRemaining:    -1,
+	}
+}
+
+// rateLimiter returns the limiter for the passed in mode, creating it from the config on first use
+func (c *Client) rateLimiter(mode AuthMode) *rateLimiter {
+	c.limitersMutex.Lock()
+	defer c.limitersMutex.Unlock()
+	if limiter, found := c.limiters[mode]; found {
+		return limiter
+	}
+
+	limit := DefaultRateLimit()
+	if mode == AuthModeAPIKey && c.config.APIKeyRateLimit != nil {
+		limit = *c.config.APIKeyRateLimit
+	} else if mode == AuthModeOAuth && c.config.OAuthRateLimit != nil {
+		limit = *c.config.OAuthRateLimit
+	}
+	limiter := newRateLimiter(limit)
+	if c.limiters == nil {
+		c.limiters = map[AuthMode]*rateLimiter{}
+	}
+	c.limiters[mode] = limiter
+	return limiter
+}
+
+// RateLimitStatus reports how much of its budget the client has left for the passed in mode, so callers can schedule
+// their work around it
+func (c *Client) RateLimitStatus(mode AuthMode) RateLimitStatus {
+	return c.rateLimiter(mode).status()
+}
+
+// refill adds the tokens earned since the last refill; the caller must hold the mutex
+func (l *rateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.lastRefill)
+	l.lastRefill = now
+	l.tokens += elapsed.Seconds() * l.rate()
+	if l.tokens > float64(l.limit.Requests) {
+		l.tokens = float64(l.limit.Requests)
+	}
+}
+
+// rate is the number of tokens earned per second
+func (l *rateLimiter) rate() float64 {
+	return float64(l.limit.Requests) / l.limit.Interval.Seconds()
+}
+
+func (l *rateLimiter) disabled() bool {
+	return l.limit.Requests <= 0 || l.limit.Interval <= 0
+}
+
+// wait blocks until a token is available or the context is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		l.mutex.Lock()
+		if l.disabled() {
+			l.mutex.Unlock()
+			return nil
+		}
+		l.refill(time.Now())
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mutex.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - l.tokens) / l.rate() * float64(time.Second))
+		l.mutex.Unlock()
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// update adapts the bucket to the budget Hubspot reports. Other processes may be spending from the same portal, so if
+// Hubspot says fewer calls remain than we think, we believe Hubspot.
+func (l *rateLimiter) update(header http.Header) {
+	if header == nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if remaining := headerInt(header, headerRateLimitRemaining, -1); remaining >= 0 {
+		l.intervalRemaining = remaining
+		if !l.disabled() {
+			l.refill(time.Now())
+			if float64(remaining) < l.tokens {
+				l.tokens = float64(remaining)
+			}
+		}
+	}
+	if dailyRemaining := headerInt(header, headerRateLimitDailyRemaining, -1); dailyRemaining >= 0 {
+		l.dailyRemaining = dailyRemaining
+	}
+}
+
+func (l *rateLimiter) status() RateLimitStatus {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	status := RateLimitStatus{
+		Remaining:         -1,
+		IntervalRemaining: l.intervalRemaining,
+		DailyRemaining:    l.dailyRemaining,
+	}
+	if !l.disabled() {
+		l.refill(time.Now())
+		status.Remaining = int(l.tokens)
+	}
+	return status
+}
@@ -0,0 +1,73 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterWaits(t *testing.T) {
+	limiter := newRateLimiter(RateLimit{
+		Requests: 5,
+		Interval: 100 * time.Millisecond,
+	})
+
+	// the bucket starts full, so the first five go straight through and the rest wait for a refill
+	start := time.Now()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, limiter.wait(context.Background()))
+		}()
+	}
+	wg.Wait()
+	assert.True(t, time.Since(start) >= 80*time.Millisecond)
+
+	// a done context stops the wait
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, limiter.wait(ctx))
+}
+
+func TestRateLimiterAdaptsToHeaders(t *testing.T) {
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerRateLimitRemaining, "2")
+		w.Header().Set(headerRateLimitDailyRemaining, "1234")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"vid": 42,
+		})
+	})
+	defer server.Close()
+
+	status := client.RateLimitStatus(AuthModeAPIKey)
+	assert.Equal(t, 100, status.Remaining)
+	assert.Equal(t, -1, status.IntervalRemaining)
+	assert.Equal(t, -1, status.DailyRemaining)
+
+	_, err := client.GetContactByEmail(context.Background(), "test@test.com")
+	require.Nil(t, err)
+
+	status = client.RateLimitStatus(AuthModeAPIKey)
+	assert.Equal(t, 2, status.Remaining)
+	assert.Equal(t, 2, status.IntervalRemaining)
+	assert.Equal(t, 1234, status.DailyRemaining)
+
+	// the oauth budget is tracked separately
+	assert.Equal(t, 100, client.RateLimitStatus(AuthModeOAuth).Remaining)
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	limiter := newRateLimiter(RateLimit{})
+	for i := 0; i < 1000; i++ {
+		require.Nil(t, limiter.wait(context.Background()))
+	}
+	assert.Equal(t, -1, limiter.status().Remaining)
+}
@@ -0,0 +1,190 @@
+package hubspot
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty"
+)
+
+// RetryPolicy controls how failed calls to Hubspot are retried. Transport errors and responses with one of the
+// RetryableStatusCodes are retried for the RetryableMethods, waiting an exponentially growing delay between attempts.
+// A 429 is always retried regardless of the method, since Hubspot did not process the request. If Hubspot sends a
+// Retry-After header, or reports that the rate limit window is used up, that is used as the delay instead.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first; 1 disables retrying
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles on each subsequent retry
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between any two attempts
+	MaxDelay time.Duration
+	// Jitter randomizes each delay between half and all of its computed value, to avoid workers retrying in lockstep
+	Jitter               bool
+	RetryableStatusCodes []int
+	RetryableMethods     []string
+}
+
+// Rate limit headers returned by Hubspot on every response
+const (
+	headerRetryAfter                 = "Retry-After"
+	headerRateLimitRemaining         = "X-HubSpot-RateLimit-Remaining"
+	headerRateLimitIntervalMS        = "X-HubSpot-RateLimit-Interval-Milliseconds"
+	headerRateLimitDailyRemaining    = "X-HubSpot-RateLimit-Daily-Remaining"
+	defaultRateLimitIntervalInMillis = 10000
+)
+
+// DefaultRetryPolicy returns the policy used when a ConfigStruct does not specify one. It makes up to 3 attempts for
+// idempotent methods on 429, 500, 502, 503 and 504 responses.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      true,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableMethods: []string{
+			http.MethodGet,
+			http.MethodPut,
+			http.MethodDelete,
+		},
+	}
+}
+
+// retryPolicy returns the configured policy, falling back to the default one
+func (c *Client) retryPolicy() *RetryPolicy {
+	if c.config.Retry != nil {
+		return c.config.Retry
+	}
+	return DefaultRetryPolicy()
+}
+
+// shouldRetry decides if the attempt that just completed should be retried and, if so, how long to wait first
+func (p *RetryPolicy) shouldRetry(ctx context.Context, httpMethod string, attempt int, response *resty.Response, reqErr error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+
+	if reqErr != nil || response == nil {
+		if !p.methodIsRetryable(httpMethod) {
+			return 0, false
+		}
+		return p.backoff(attempt), true
+	}
+
+	statusCode := response.StatusCode()
+	if statusCode == http.StatusTooManyRequests {
+		// once the daily quota is gone, nothing will succeed until tomorrow
+		if response.Header().Get(headerRateLimitDailyRemaining) == "0" {
+			return 0, false
+		}
+	} else if !p.methodIsRetryable(httpMethod) {
+		return 0, false
+	}
+	if !p.statusIsRetryable(statusCode) {
+		return 0, false
+	}
+
+	if delay, found := retryAfter(response.Header()); found {
+		// honour the server, but not for longer than we are willing to wait
+		if p.MaxDelay > 0 && delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+		return delay, true
+	}
+	if statusCode == http.StatusTooManyRequests && response.Header().Get(headerRateLimitRemaining) == "0" {
+		// the rolling window is used up, so wait for it to pass, but not longer than we are willing to
+		interval := time.Duration(headerInt(response.Header(), headerRateLimitIntervalMS, defaultRateLimitIntervalInMillis)) * time.Millisecond
+		if p.MaxDelay > 0 && interval > p.MaxDelay {
+			interval = p.MaxDelay
+		}
+		return interval, true
+	}
+	return p.backoff(attempt), true
+}
+
+// backoff computes the exponential delay before the next attempt
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter && delay > 1 {
+		half := delay / 2
+		delay = half + time.Duration(rand.Int63n(int64(half)+1))
+	}
+	return delay
+}
+
+func (p *RetryPolicy) methodIsRetryable(httpMethod string) bool {
+	for _, m := range p.RetryableMethods {
+		if strings.EqualFold(m, httpMethod) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) statusIsRetryable(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header, which can be either a number of seconds or an HTTP date
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := strings.TrimSpace(header.Get(headerRetryAfter))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		delay := time.Until(at)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// headerInt reads an integer header, returning the fallback if it is missing or malformed
+func headerInt(header http.Header, key string, fallback int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(header.Get(key)))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// sleepContext waits for the delay to pass, returning early with the context's error if it is done first
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,145 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 5 * time.Millisecond
+	return policy
+}
+
+func TestRetryOnServerError(t *testing.T) {
+	calls := 0
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"vid": 42,
+		})
+	})
+	defer server.Close()
+	client.Config().Retry = testRetryPolicy()
+
+	contact, err := client.GetContactByEmail(context.Background(), "test@test.com")
+	require.Nil(t, err)
+	assert.Equal(t, int64(42), contact.VID)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	calls := 0
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+	client.Config().Retry = testRetryPolicy()
+
+	_, err := client.GetContactByEmail(context.Background(), "test@test.com")
+	require.NotNil(t, err)
+	apiErr, cOK := err.(APIError)
+	require.True(t, cOK)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.HTTPCode)
+	assert.Equal(t, 3, calls)
+
+	// POST is not retried on a 5xx, since we don't know if Hubspot processed it
+	calls = 0
+	err = client.CreateOrUpdateContact(context.Background(), &Contact{Email: "test@test.com"})
+	require.NotNil(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	calls := 0
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"vid": 42,
+		})
+	})
+	defer server.Close()
+	policy := testRetryPolicy()
+	policy.MaxDelay = 2 * time.Second
+	client.Config().Retry = policy
+
+	// a 429 is retried even for a POST
+	start := time.Now()
+	err := client.CreateOrUpdateContact(context.Background(), &Contact{Email: "test@test.com"})
+	require.Nil(t, err)
+	assert.Equal(t, 2, calls)
+	assert.True(t, time.Since(start) >= time.Second)
+}
+
+func TestRetryCapsRetryAfter(t *testing.T) {
+	calls := 0
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"vid": 42,
+		})
+	})
+	defer server.Close()
+	client.Config().Retry = testRetryPolicy()
+
+	// an hour is longer than MaxDelay, so the retry waits for MaxDelay instead
+	start := time.Now()
+	_, err := client.GetContactByEmail(context.Background(), "test@test.com")
+	require.Nil(t, err)
+	assert.Equal(t, 2, calls)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestRetryStopsOnDailyLimit(t *testing.T) {
+	calls := 0
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set(headerRateLimitDailyRemaining, "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	defer server.Close()
+	client.Config().Retry = testRetryPolicy()
+
+	_, err := client.GetContactByEmail(context.Background(), "test@test.com")
+	require.NotNil(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := &RetryPolicy{
+		BaseDelay: 100 * time.Millisecond,
+		MaxDelay:  time.Second,
+	}
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, time.Second, policy.backoff(10))
+
+	policy.Jitter = true
+	for i := 0; i < 100; i++ {
+		delay := policy.backoff(2)
+		assert.True(t, delay >= 100*time.Millisecond && delay <= 200*time.Millisecond)
+	}
+}
@@ -0,0 +1,419 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SearchOperator compares a property to the values in a search filter
+type SearchOperator string
+
+// The operators accepted by the CRM search API. BETWEEN takes a low and a high value, IN and NOT_IN take one or more
+// values, HAS_PROPERTY and NOT_HAS_PROPERTY take none and the rest take exactly one.
+const (
+	EQ                 SearchOperator = "EQ"
+	NEQ                SearchOperator = "NEQ"
+	LT                 SearchOperator = "LT"
+	LTE                SearchOperator = "LTE"
+	GT                 SearchOperator = "GT"
+	GTE                SearchOperator = "GTE"
+	BETWEEN            SearchOperator = "BETWEEN"
+	IN                 SearchOperator = "IN"
+	NOT_IN             SearchOperator = "NOT_IN"
+	HAS_PROPERTY       SearchOperator = "HAS_PROPERTY"
+	NOT_HAS_PROPERTY   SearchOperator = "NOT_HAS_PROPERTY"
+	CONTAINS_TOKEN     SearchOperator = "CONTAINS_TOKEN"
+	NOT_CONTAINS_TOKEN SearchOperator = "NOT_CONTAINS_TOKEN"
+)
+
+// Hubspot's limits on a single search request
+const (
+	searchMaxFilterGroups    = 5
+	searchMaxFiltersPerGroup = 6
+	searchMaxFilters         = 18
+	searchMaxSorts           = 1
+	searchPageSize           = 100
+)
+
+// SearchQuery is a search of one type of CRM object, such as "contacts", "companies", "deals" or "tickets". It is built
+// up fluently and then run with Iterator:
+//
+//	it := client.Search("contacts").
+//		Where("lifecyclestage", hubspot.EQ, "customer").
+//		Where("createdate", hubspot.GT, since).
+//		Or("email", hubspot.CONTAINS_TOKEN, "*@wagz.com").
+//		Sort("createdate", hubspot.SortOrderDescending).
+//		Properties("email", "firstname").
+//		Iterator(ctx)
+//
+// Filters added with Where must all match. Or starts a new group of filters, and a result only needs to match one group.
+// Mistakes in the query, such as the wrong number of values for an operator or more filters than Hubspot allows, are
+// reported by Validate and by the iterator before anything is sent.
+type SearchQuery struct {
+	client     *Client
+	objectType string
+	groups     []searchFilterGroup
+	sorts      []searchSort
+	properties []string
+	query      string
+	limit      int
+	after      string
+	err        error
+}
+
+type searchFilterGroup struct {
+	Filters []searchFilter `json:"filters"`
+}
+
+type searchFilter struct {
+	PropertyName string         `json:"propertyName"`
+	Operator     SearchOperator `json:"operator"`
+	Value        string         `json:"value,omitempty"`
+	HighValue    string         `json:"highValue,omitempty"`
+	Values       []string       `json:"values,omitempty"`
+}
+
+type searchSort struct {
+	PropertyName string `json:"propertyName"`
+	Direction    string `json:"direction"`
+}
+
+// searchRequest is the body sent to Hubspot for a search
+type searchRequest struct {
+	FilterGroups []searchFilterGroup `json:"filterGroups"`
+	Sorts        []searchSort        `json:"sorts,omitempty"`
+	Properties   []string            `json:"properties,omitempty"`
+	Query        string              `json:"query,omitempty"`
+	Limit        int                 `json:"limit"`
+	After        string              `json:"after,omitempty"`
+}
+
+// searchResponse is a single page of search results as returned by Hubspot
+type searchResponse struct {
+	Total   int64                  `json:"total"`
+	Results []searchResultResponse `json:"results"`
+	Paging  *struct {
+		Next *struct {
+			After string `json:"after"`
+		} `json:"next"`
+	} `json:"paging"`
+}
+
+type searchResultResponse struct {
+	ID         string                   `json:"id"`
+	Properties map[string]PropertyValue `json:"properties"`
+	CreatedAt  time.Time                `json:"createdAt"`
+	UpdatedAt  time.Time                `json:"updatedAt"`
+	Archived   bool                     `json:"archived"`
+}
+
+// SearchResult is a single object found by a search. Properties holds the requested properties, or Hubspot's default
+// ones for the object type if none were requested. Properties without a value are blank.
+type SearchResult struct {
+	ID         string
+	Properties map[string]string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Archived   bool
+}
+
+// Search starts a search of the objects of the type, such as "contacts", "companies", "deals" or "tickets"
+//
+// API Doc: https://developers.hubspot.com/docs/api/crm/search
+func (c *Client) Search(objectType string) *SearchQuery {
+	return &SearchQuery{
+		client:     c,
+		objectType: objectType,
+	}
+}
+
+// Search calls Client.Search on the DefaultClient
+func Search(objectType string) *SearchQuery {
+	return DefaultClient().Search(objectType)
+}
+
+// Where adds a filter that must match along with the others in the current group. Values can be strings, numbers,
+// booleans, time.Time or anything implementing fmt.Stringer; times are sent as milliseconds since the epoch, which is how
+// Hubspot stores dates.
+func (q *SearchQuery) Where(property string, operator SearchOperator, values ...interface{}) *SearchQuery {
+	if len(q.groups) == 0 {
+		q.groups = append(q.groups, searchFilterGroup{})
+	}
+	filter, err := newSearchFilter(property, operator, values)
+	if err != nil {
+		q.setErr(err)
+		return q
+	}
+	last := &q.groups[len(q.groups)-1]
+	last.Filters = append(last.Filters, filter)
+	return q
+}
+
+// Or starts a new group of filters with this filter in it. A result matches the search if it matches every filter in
+// any one of the groups.
+func (q *SearchQuery) Or(property string, operator SearchOperator, values ...interface{}) *SearchQuery {
+	q.groups = append(q.groups, searchFilterGroup{})
+	return q.Where(property, operator, values...)
+}
+
+// Sort orders the results by the property
+func (q *SearchQuery) Sort(property string, order SortOrder) *SearchQuery {
+	direction := "ASCENDING"
+	if order == SortOrderDescending {
+		direction = "DESCENDING"
+	}
+	q.sorts = append(q.sorts, searchSort{
+		PropertyName: property,
+		Direction:    direction,
+	})
+	return q
+}
+
+// Properties sets which properties are returned for each result
+func (q *SearchQuery) Properties(properties ...string) *SearchQuery {
+	q.properties = append(q.properties, properties...)
+	return q
+}
+
+// Query adds a free text search across the object type's default searchable properties
+func (q *SearchQuery) Query(text string) *SearchQuery {
+	q.query = text
+	return q
+}
+
+// Limit sets the number of results fetched per page, up to 100. It defaults to 100.
+func (q *SearchQuery) Limit(limit int) *SearchQuery {
+	q.limit = limit
+	return q
+}
+
+// After resumes the search from a cursor returned by SearchIterator.After
+func (q *SearchQuery) After(after string) *SearchQuery {
+	q.after = after
+	return q
+}
+
+// Validate checks the query against Hubspot's rules without sending it
+func (q *SearchQuery) Validate() error {
+	if q.err != nil {
+		return q.err
+	}
+	if q.objectType == "" {
+		return searchError(CodeSearchMissingData, "you must specify the type of object to search")
+	}
+	if len(q.groups) > searchMaxFilterGroups {
+		return searchError(CodeSearchTooManyFilters, fmt.Sprintf("a search can have at most %d filter groups, this one has %d", searchMaxFilterGroups, len(q.groups)))
+	}
+	total := 0
+	for i := range q.groups {
+		if len(q.groups[i].Filters) > searchMaxFiltersPerGroup {
+			return searchError(CodeSearchTooManyFilters, fmt.Sprintf("a filter group can have at most %d filters, group %d has %d", searchMaxFiltersPerGroup, i+1, len(q.groups[i].Filters)))
+		}
+		total += len(q.groups[i].Filters)
+	}
+	if total > searchMaxFilters {
+		return searchError(CodeSearchTooManyFilters, fmt.Sprintf("a search can have at most %d filters in total, this one has %d", searchMaxFilters, total))
+	}
+	if len(q.sorts) > searchMaxSorts {
+		return searchError(CodeSearchInvalidSort, fmt.Sprintf("a search can be sorted by at most %d property", searchMaxSorts))
+	}
+	if q.limit < 0 || q.limit > searchPageSize {
+		return searchError(CodeSearchInvalidLimit, fmt.Sprintf("the limit must be between 0 and %d (0 uses the default)", searchPageSize))
+	}
+	return nil
+}
+
+// Iterator runs the search, returning an iterator that fetches pages of results as they are needed
+func (q *SearchQuery) Iterator(ctx context.Context) *SearchIterator {
+	it := &SearchIterator{}
+	it.pager = newCursorPager(ctx, q.after, func(ctx context.Context, cursor interface{}) (int, interface{}, bool, error) {
+		return it.fetch(ctx, q, cursor.(string))
+	})
+	it.err = q.Validate()
+	return it
+}
+
+// request builds the body for the page of results after the cursor
+func (q *SearchQuery) request(after string) searchRequest {
+	limit := q.limit
+	if limit == 0 {
+		limit = searchPageSize
+	}
+	groups := q.groups
+	if groups == nil {
+		groups = []searchFilterGroup{}
+	}
+	return searchRequest{
+		FilterGroups: groups,
+		Sorts:        q.sorts,
+		Properties:   q.properties,
+		Query:        q.query,
+		Limit:        limit,
+		After:        after,
+	}
+}
+
+// setErr keeps the first mistake made while building the query
+func (q *SearchQuery) setErr(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// SearchIterator walks the results of a search, fetching pages from Hubspot as they are needed. It is used the same way
+// as a ContactIterator.
+type SearchIterator struct {
+	pager
+	page  []SearchResult
+	total int64
+}
+
+// Next advances to the next result, fetching another page if needed. It returns false when there are no more results or
+// an error occurred, which is then available from Err.
+func (it *SearchIterator) Next() bool {
+	return it.next()
+}
+
+// fetch gets the page of results after the cursor, returning the cursor of the following page
+func (it *SearchIterator) fetch(ctx context.Context, q *SearchQuery, after string) (int, interface{}, bool, error) {
+	result := searchResponse{}
+	_, err := q.client.prepareCall(ctx, EndpointSearchObjects, map[string]string{
+		":objectType": q.objectType,
+	}, q.request(after), &result)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+			apiErr.SystemCode = CodeSearchCouldNotBeCompleted
+			return 0, after, false, apiErr
+		}
+		return 0, after, false, err
+	}
+
+	it.page = make([]SearchResult, len(result.Results))
+	for i, found := range result.Results {
+		properties := make(map[string]string, len(found.Properties))
+		for name, value := range found.Properties {
+			properties[name] = value.String()
+		}
+		it.page[i] = SearchResult{
+			ID:         found.ID,
+			Properties: properties,
+			CreatedAt:  found.CreatedAt,
+			UpdatedAt:  found.UpdatedAt,
+			Archived:   found.Archived,
+		}
+	}
+	it.total = result.Total
+	next := ""
+	if result.Paging != nil && result.Paging.Next != nil {
+		next = result.Paging.Next.After
+	}
+	return len(it.page), next, next != "", nil
+}
+
+// Result returns the current result. It is only valid after a call to Next returns true.
+func (it *SearchIterator) Result() SearchResult {
+	if i := it.current(); i >= 0 {
+		return it.page[i]
+	}
+	return SearchResult{}
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *SearchIterator) Err() error {
+	return it.err
+}
+
+// Total returns the number of results Hubspot found, once the first page has been fetched
+func (it *SearchIterator) Total() int64 {
+	return it.total
+}
+
+// After returns a cursor to resume the search from with SearchQuery.After. See pager.cursor for where it points. It is
+// blank once the last page has been read.
+func (it *SearchIterator) After() string {
+	after, _ := it.cursor().(string)
+	return after
+}
+
+// newSearchFilter checks that the operator has the right number of values and converts them to what Hubspot expects
+func newSearchFilter(property string, operator SearchOperator, values []interface{}) (searchFilter, error) {
+	filter := searchFilter{
+		PropertyName: property,
+		Operator:     operator,
+	}
+	if property == "" {
+		return filter, searchError(CodeSearchInvalidFilter, "a filter must have a property name")
+	}
+
+	formatted := make([]string, len(values))
+	for i := range values {
+		formatted[i] = formatSearchValue(values[i])
+	}
+
+	switch operator {
+	case EQ, NEQ, LT, LTE, GT, GTE, CONTAINS_TOKEN, NOT_CONTAINS_TOKEN:
+		if len(formatted) != 1 {
+			return filter, searchError(CodeSearchInvalidFilter, fmt.Sprintf("%s on %s takes exactly one value, got %d", operator, property, len(formatted)))
+		}
+		filter.Value = formatted[0]
+	case BETWEEN:
+		if len(formatted) != 2 {
+			return filter, searchError(CodeSearchInvalidFilter, fmt.Sprintf("BETWEEN on %s takes a low and a high value, got %d values", property, len(formatted)))
+		}
+		filter.Value = formatted[0]
+		filter.HighValue = formatted[1]
+	case IN, NOT_IN:
+		if len(formatted) == 0 {
+			return filter, searchError(CodeSearchInvalidFilter, fmt.Sprintf("%s on %s takes at least one value", operator, property))
+		}
+		filter.Values = formatted
+	case HAS_PROPERTY, NOT_HAS_PROPERTY:
+		if len(formatted) != 0 {
+			return filter, searchError(CodeSearchInvalidFilter, fmt.Sprintf("%s on %s does not take a value", operator, property))
+		}
+	default:
+		return filter, searchError(CodeSearchInvalidFilter, fmt.Sprintf("%q is not a search operator", operator))
+	}
+	return filter, nil
+}
+
+// formatSearchValue converts a value to the string Hubspot expects in a filter
+func formatSearchValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return strconv.FormatInt(toMilliseconds(v), 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return strings.TrimSpace(fmt.Sprintf("%v", value))
+}
+
+// toMilliseconds converts a time to the milliseconds since the epoch Hubspot uses, with the zero time as 0
+func toMilliseconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// searchError is returned for a query that Hubspot would reject
+func searchError(code, message string) APIError {
+	return APIError{
+		HTTPCode:   http.StatusBadRequest,
+		SystemCode: code,
+		Message:    message,
+		Body:       nil,
+	}
+}
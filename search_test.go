@@ -0,0 +1,148 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchBuilder(t *testing.T) {
+	client := NewClient(&ConfigStruct{RootURL: "http://localhost", HubspotAPIKey: "test-key"})
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := client.Search("contacts").
+		Where("lifecyclestage", EQ, "customer").
+		Where("createdate", BETWEEN, start, start.Add(time.Hour)).
+		Where("hs_lead_status", IN, "NEW", "OPEN").
+		Or("email", HAS_PROPERTY).
+		Where("num_employees", GT, 10).
+		Sort("createdate", SortOrderDescending).
+		Properties("email", "firstname")
+	require.Nil(t, query.Validate())
+
+	body, err := json.Marshal(query.request(""))
+	require.Nil(t, err)
+	assert.JSONEq(t, `{
+		"filterGroups": [
+			{"filters": [
+				{"propertyName": "lifecyclestage", "operator": "EQ", "value": "customer"},
+				{"propertyName": "createdate", "operator": "BETWEEN", "value": "1577836800000", "highValue": "1577840400000"},
+				{"propertyName": "hs_lead_status", "operator": "IN", "values": ["NEW", "OPEN"]}
+			]},
+			{"filters": [
+				{"propertyName": "email", "operator": "HAS_PROPERTY"},
+				{"propertyName": "num_employees", "operator": "GT", "value": "10"}
+			]}
+		],
+		"sorts": [{"propertyName": "createdate", "direction": "DESCENDING"}],
+		"properties": ["email", "firstname"],
+		"limit": 100
+	}`, string(body))
+
+	// the wrong number of values for an operator
+	err = client.Search("contacts").Where("createdate", BETWEEN, start).Validate()
+	require.NotNil(t, err)
+	assert.Equal(t, CodeSearchInvalidFilter, err.(APIError).SystemCode)
+	err = client.Search("contacts").Where("email", HAS_PROPERTY, "x").Validate()
+	require.NotNil(t, err)
+	assert.Equal(t, CodeSearchInvalidFilter, err.(APIError).SystemCode)
+	err = client.Search("contacts").Where("email", IN).Validate()
+	require.NotNil(t, err)
+	assert.Equal(t, CodeSearchInvalidFilter, err.(APIError).SystemCode)
+	err = client.Search("contacts").Where("email", SearchOperator("LIKE"), "x").Validate()
+	require.NotNil(t, err)
+	assert.Equal(t, CodeSearchInvalidFilter, err.(APIError).SystemCode)
+
+	// Hubspot's limits
+	tooManyGroups := client.Search("contacts")
+	for i := 0; i < 6; i++ {
+		tooManyGroups.Or("email", HAS_PROPERTY)
+	}
+	err = tooManyGroups.Validate()
+	require.NotNil(t, err)
+	assert.Equal(t, CodeSearchTooManyFilters, err.(APIError).SystemCode)
+
+	tooManyFilters := client.Search("contacts")
+	for i := 0; i < 7; i++ {
+		tooManyFilters.Where("email", HAS_PROPERTY)
+	}
+	err = tooManyFilters.Validate()
+	require.NotNil(t, err)
+	assert.Equal(t, CodeSearchTooManyFilters, err.(APIError).SystemCode)
+
+	err = client.Search("contacts").Sort("email", SortOrderAscending).Sort("createdate", SortOrderAscending).Validate()
+	require.NotNil(t, err)
+	assert.Equal(t, CodeSearchInvalidSort, err.(APIError).SystemCode)
+	err = client.Search("contacts").Limit(500).Validate()
+	require.NotNil(t, err)
+	assert.Equal(t, CodeSearchInvalidLimit, err.(APIError).SystemCode)
+	assert.Equal(t, "the limit must be between 0 and 100 (0 uses the default)", err.(APIError).Message)
+	assert.Nil(t, client.Search("contacts").Limit(0).Validate())
+	err = client.Search("").Validate()
+	require.NotNil(t, err)
+	assert.Equal(t, CodeSearchMissingData, err.(APIError).SystemCode)
+}
+
+func TestSearchIterator(t *testing.T) {
+	requests := []searchRequest{}
+	status := http.StatusOK
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/crm/v3/objects/deals/search", r.URL.Path)
+		request := searchRequest{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+		requests = append(requests, request)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if request.After == "" {
+			fmt.Fprint(w, `{"total": 3, "results": [
+				{"id": "1", "properties": {"dealname": "First", "amount": null}, "createdAt": "2020-01-01T00:00:00Z", "updatedAt": "2020-01-02T00:00:00Z", "archived": false},
+				{"id": "2", "properties": {"dealname": "Second"}}
+			], "paging": {"next": {"after": "2"}}}`)
+			return
+		}
+		fmt.Fprint(w, `{"total": 3, "results": [{"id": "3", "properties": {"dealname": "Third"}}]}`)
+	})
+	defer server.Close()
+
+	// nothing is sent for an invalid query
+	it := client.Search("deals").Where("amount", BETWEEN, 1).Iterator(context.Background())
+	assert.False(t, it.Next())
+	assert.Equal(t, CodeSearchInvalidFilter, it.Err().(APIError).SystemCode)
+	assert.Empty(t, requests)
+
+	it = client.Search("deals").Where("amount", GT, 100.5).Limit(2).Iterator(context.Background())
+	ids := []string{}
+	for it.Next() {
+		ids = append(ids, it.Result().ID)
+		if it.Result().ID == "1" {
+			assert.Equal(t, "First", it.Result().Properties["dealname"])
+			assert.Equal(t, "", it.Result().Properties["amount"])
+			assert.Equal(t, 2020, it.Result().CreatedAt.Year())
+		}
+	}
+	require.Nil(t, it.Err())
+	assert.Equal(t, []string{"1", "2", "3"}, ids)
+	assert.Equal(t, int64(3), it.Total())
+	assert.Equal(t, "", it.After())
+	require.Len(t, requests, 2)
+	assert.Equal(t, 2, requests[0].Limit)
+	assert.Equal(t, "100.5", requests[0].FilterGroups[0].Filters[0].Value)
+	assert.Equal(t, "2", requests[1].After)
+
+	// resuming from a cursor
+	it = client.Search("deals").After("2").Iterator(context.Background())
+	require.True(t, it.Next())
+	assert.Equal(t, "3", it.Result().ID)
+
+	status = http.StatusBadRequest
+	it = client.Search("deals").Iterator(context.Background())
+	assert.False(t, it.Next())
+	assert.Equal(t, CodeSearchCouldNotBeCompleted, it.Err().(APIError).SystemCode)
+}
@@ -1,18 +1,103 @@
 package hubspot
 
-// System codes are used to convey specific statuses in return objects
+// System codes are used to convey specific statuses in return objects. Each code is a short, stable identifier that is
+// safe to compare against; the human readable explanation is in the Message of the APIError.
 const (
-	CodeContactCouldNotBeCreated = "the contact could not be created at hubspot; you should check the Message field"
-	CodeContactNoEmail           = "you failed to specify an email for that contact"
-	CodeContactNotFound          = "contact could not be found"
-	CodeContactVIDZero           = "the contact VID cannot be 0 for this action"
+	CodeContactCouldNotBeCreated  = "contact_could_not_be_created"
+	CodeContactCouldNotBeUpdated  = "contact_could_not_be_updated"
+	CodeContactCouldNotBeMerged   = "contact_could_not_be_merged"
+	CodeContactMergeSameVID       = "contact_merge_same_vid"
+	CodeContactCouldNotBeDeleted  = "contact_could_not_be_deleted"
+	CodeContactNoEmail            = "contact_no_email"
+	CodeContactInvalidEmail       = "contact_invalid_email"
+	CodeContactNoUTK              = "contact_no_utk"
+	CodeContactNotFound           = "contact_not_found"
+	CodeContactVIDZero            = "contact_vid_zero"
+	CodeContactBatchMissingData   = "contact_batch_missing_data"
+	CodeContactCouldNotBeListed   = "contact_could_not_be_listed"
+	CodeContactCouldNotBeSearched = "contact_could_not_be_searched"
+	CodeContactSearchNoQuery      = "contact_search_no_query"
+	CodeContactBatchHadFailures   = "contact_batch_had_failures"
 
-	CodeEventTypeCouldNotBeCreated = "the event type could not be created"
-	CodeEventTypeMissingData       = "the input is missing required information"
-	CodeEventTypeCouldNotBeDeleted = "that event type could not be deleted"
+	CodeEventTypeCouldNotBeCreated = "event_type_could_not_be_created"
+	CodeEventTypeMissingData       = "event_type_missing_data"
+	CodeEventTypeCouldNotBeDeleted = "event_type_could_not_be_deleted"
 
-	CodeEventCouldNotBeCreated = "the event could not be created"
-	CodeEventMissingData       = "the input is missing required information"
+	CodeEventCouldNotBeCreated = "event_could_not_be_created"
+	CodeEventMissingData       = "event_missing_data"
 
-	CodeGeneralError = "a general error occurred"
+	CodeCompanyCouldNotBeCreated        = "company_could_not_be_created"
+	CodeCompanyCouldNotBeUpdated        = "company_could_not_be_updated"
+	CodeCompanyCouldNotBeDeleted        = "company_could_not_be_deleted"
+	CodeCompanyCouldNotBeListed         = "company_could_not_be_listed"
+	CodeCompanyContactCouldNotBeAdded   = "company_contact_could_not_be_added"
+	CodeCompanyContactCouldNotBeRemoved = "company_contact_could_not_be_removed"
+	CodeCompanyMissingData              = "company_missing_data"
+	CodeCompanyNoDomain                 = "company_no_domain"
+	CodeCompanyNotFound                 = "company_not_found"
+	CodeCompanyIDZero                   = "company_id_zero"
+
+	CodeDealCouldNotBeCreated = "deal_could_not_be_created"
+	CodeDealCouldNotBeUpdated = "deal_could_not_be_updated"
+	CodeDealCouldNotBeDeleted = "deal_could_not_be_deleted"
+	CodeDealCouldNotBeListed  = "deal_could_not_be_listed"
+	CodeDealMissingData       = "deal_missing_data"
+	CodeDealNotFound          = "deal_not_found"
+	CodeDealIDZero            = "deal_id_zero"
+
+	CodeTicketCouldNotBeCreated = "ticket_could_not_be_created"
+	CodeTicketCouldNotBeUpdated = "ticket_could_not_be_updated"
+	CodeTicketCouldNotBeDeleted = "ticket_could_not_be_deleted"
+	CodeTicketCouldNotBeListed  = "ticket_could_not_be_listed"
+	CodeTicketMissingData       = "ticket_missing_data"
+	CodeTicketNotFound          = "ticket_not_found"
+	CodeTicketIDZero            = "ticket_id_zero"
+
+	CodeEngagementCouldNotBeCreated = "engagement_could_not_be_created"
+	CodeEngagementCouldNotBeUpdated = "engagement_could_not_be_updated"
+	CodeEngagementCouldNotBeDeleted = "engagement_could_not_be_deleted"
+	CodeEngagementCouldNotBeListed  = "engagement_could_not_be_listed"
+	CodeEngagementMissingData       = "engagement_missing_data"
+	CodeEngagementNotFound          = "engagement_not_found"
+	CodeEngagementIDZero            = "engagement_id_zero"
+
+	CodePipelineCouldNotBeCreated      = "pipeline_could_not_be_created"
+	CodePipelineCouldNotBeUpdated      = "pipeline_could_not_be_updated"
+	CodePipelineCouldNotBeDeleted      = "pipeline_could_not_be_deleted"
+	CodePipelineCouldNotBeListed       = "pipeline_could_not_be_listed"
+	CodePipelineMissingData            = "pipeline_missing_data"
+	CodePipelineNotFound               = "pipeline_not_found"
+	CodePipelineStageCouldNotBeCreated = "pipeline_stage_could_not_be_created"
+	CodePipelineStageCouldNotBeUpdated = "pipeline_stage_could_not_be_updated"
+	CodePipelineStageCouldNotBeDeleted = "pipeline_stage_could_not_be_deleted"
+	CodePipelineStageNotFound          = "pipeline_stage_not_found"
+	CodePipelineStageNotInPipeline     = "pipeline_stage_not_in_pipeline"
+
+	CodeAssociationCouldNotBeCreated = "association_could_not_be_created"
+	CodeAssociationCouldNotBeRemoved = "association_could_not_be_removed"
+	CodeAssociationCouldNotBeListed  = "association_could_not_be_listed"
+	CodeAssociationIDZero            = "association_id_zero"
+
+	CodeSearchMissingData         = "search_missing_data"
+	CodeSearchInvalidFilter       = "search_invalid_filter"
+	CodeSearchTooManyFilters      = "search_too_many_filters"
+	CodeSearchInvalidSort         = "search_invalid_sort"
+	CodeSearchInvalidLimit        = "search_invalid_limit"
+	CodeSearchCouldNotBeCompleted = "search_could_not_be_completed"
+
+	CodeOAuthTokenUnavailable     = "oauth_token_unavailable"
+	CodeOAuthMissingData          = "oauth_missing_data"
+	CodeOAuthCouldNotExchangeCode = "oauth_could_not_exchange_code"
+	CodeOAuthTokenNotFound        = "oauth_token_not_found"
+	CodeOAuthCouldNotRevoke       = "oauth_could_not_revoke"
+
+	CodePropertyCouldNotBeConverted = "property_could_not_be_converted"
+
+	CodeRequestEndpointNotFound   = "request_error_endpoint_not_found"
+	CodeRequestBadQueryString     = "request_error_bad_query_string"
+	CodeRequestError              = "request_error"
+	CodeResponseCouldNotBeDecoded = "response_could_not_be_decoded"
+	CodeHubspotError              = "error"
+
+	CodeGeneralError = "general_error"
 )
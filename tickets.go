@@ -0,0 +1,513 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ticketBatchSize is the most tickets Hubspot will accept in a single batch create or update
+const ticketBatchSize = 100
+
+// TicketPriority is how urgent a ticket is
+type TicketPriority string
+
+// The priorities Hubspot defines for tickets
+const (
+	TicketPriorityLow    TicketPriority = "LOW"
+	TicketPriorityMedium TicketPriority = "MEDIUM"
+	TicketPriorityHigh   TicketPriority = "HIGH"
+)
+
+// Ticket is a customer issue tracked in Service Hub. ID is 0 until the ticket has been created.
+//
+// Pipeline and Stage hold the internal IDs of the ticket pipeline and stage, and both are needed to create a ticket.
+// Use a PipelineCache for PipelineObjectTypeTickets to look them up by label and to check them with ValidateTicket.
+// The other fields work the same way as they do on a Company.
+type Ticket struct {
+	ID       int64          `hubspot:"-"`
+	Subject  string         `hubspot:"subject,omitempty"`
+	Content  string         `hubspot:"content,omitempty"`
+	Pipeline string         `hubspot:"hs_pipeline,omitempty"`
+	Stage    string         `hubspot:"hs_pipeline_stage,omitempty"`
+	Priority TicketPriority `hubspot:"hs_ticket_priority,omitempty"`
+	Category string         `hubspot:"hs_ticket_category,omitempty"`
+	// Source is where the ticket came from, such as "EMAIL", "CHAT", "PHONE" or "FORM"
+	Source               string            `hubspot:"source_type,omitempty"`
+	OwnerID              string            `hubspot:"hubspot_owner_id,omitempty"`
+	AdditionalProperties *[]ObjectProperty `hubspot:"-"`
+	ClearProperties      []string          `hubspot:"-"`
+	Properties           ObjectProperties  `hubspot:"-"`
+}
+
+// ticketProperties are the properties fetched for a ticket when none are asked for, which are those of the fields
+var ticketProperties = []string{
+	"subject",
+	"content",
+	"hs_pipeline",
+	"hs_pipeline_stage",
+	"hs_ticket_priority",
+	"hs_ticket_category",
+	"source_type",
+	"hubspot_owner_id",
+	"createdate",
+	"hs_lastmodifieddate",
+}
+
+// TicketListOptions controls a ticket listing. Hubspot only returns the properties that are asked for; without any
+// Properties, those of the Ticket fields are fetched.
+type TicketListOptions struct {
+	Properties []string
+	// PropertiesWithHistory also returns the versions of the properties
+	PropertiesWithHistory bool
+	// Offset resumes a listing from a cursor returned by TicketIterator.Offset
+	Offset int64
+}
+
+// ticketResponse is a ticket as returned by Hubspot
+type ticketResponse struct {
+	ObjectID   int64                             `json:"objectId"`
+	ObjectType string                            `json:"objectType"`
+	PortalID   int64                             `json:"portalId"`
+	IsDeleted  bool                              `json:"isDeleted"`
+	Properties map[string]objectPropertyResponse `json:"properties"`
+}
+
+// ticketListResponse is a single page of tickets as returned by Hubspot
+type ticketListResponse struct {
+	Objects []ticketResponse `json:"objects"`
+	HasMore bool             `json:"hasMore"`
+	Offset  int64            `json:"offset"`
+}
+
+// ticketBatchRequest is a single ticket in a batch update
+type ticketBatchRequest struct {
+	ObjectID   int64            `json:"objectId"`
+	Properties []ObjectProperty `json:"properties"`
+}
+
+// CreateTicket creates a new ticket. The ID of the ticket is filled in once it has been created.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/tickets/create-ticket
+func (c *Client) CreateTicket(ctx context.Context, ticket *Ticket) error {
+	props, err := ticket.convertTicketForCreate()
+	if err != nil {
+		return err
+	}
+
+	result := ticketResponse{}
+	_, err = c.prepareCall(ctx, EndpointCreateTicket, map[string]string{}, props, &result)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+			apiErr.SystemCode = CodeTicketCouldNotBeCreated
+			return apiErr
+		}
+		return err
+	}
+	ticket.populateTicketFields(result)
+	return nil
+}
+
+// CreateTicket calls Client.CreateTicket on the DefaultClient with a background context
+func CreateTicket(ticket *Ticket) error {
+	return DefaultClient().CreateTicket(context.Background(), ticket)
+}
+
+// UpdateTicket updates an existing ticket using its ID. Only the properties that are set, and those in
+// ClearProperties, are changed. The ticket is filled in with what Hubspot returns.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/tickets/update-ticket
+func (c *Client) UpdateTicket(ctx context.Context, ticket *Ticket) error {
+	if ticket.ID == 0 {
+		return ticketIDZeroError("updating")
+	}
+	props, err := ticket.convertTicketToProperties()
+	if err != nil {
+		return err
+	}
+
+	result := ticketResponse{}
+	_, err = c.prepareCall(ctx, EndpointUpdateTicket, map[string]string{
+		":ticketID": fmt.Sprintf("%d", ticket.ID),
+	}, props, &result)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeTicketNotFound
+				return apiErr
+			}
+			apiErr.SystemCode = CodeTicketCouldNotBeUpdated
+			return apiErr
+		}
+		return err
+	}
+	ticket.populateTicketFields(result)
+	return nil
+}
+
+// UpdateTicket calls Client.UpdateTicket on the DefaultClient with a background context
+func UpdateTicket(ticket *Ticket) error {
+	return DefaultClient().UpdateTicket(context.Background(), ticket)
+}
+
+// BatchCreateTickets creates many tickets at once, filling in their IDs. The tickets are sent in batches of up to 100,
+// which is the most Hubspot allows; if a batch is rejected the error is returned straight away, and the tickets in the
+// batches before it have already been created.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/tickets/batch-create-tickets
+func (c *Client) BatchCreateTickets(ctx context.Context, tickets []Ticket) error {
+	if len(tickets) == 0 {
+		return ticketMissingDataError("you must specify at least one ticket to create")
+	}
+	send := make([][]ObjectProperty, len(tickets))
+	for i := range tickets {
+		props, err := tickets[i].convertTicketForCreate()
+		if err != nil {
+			return err
+		}
+		send[i] = props
+	}
+
+	for start := 0; start < len(send); start += ticketBatchSize {
+		end := start + ticketBatchSize
+		if end > len(send) {
+			end = len(send)
+		}
+		result := []ticketResponse{}
+		_, err := c.prepareCall(ctx, EndpointBatchCreateTickets, map[string]string{}, send[start:end], &result)
+		if err != nil {
+			if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+				apiErr.SystemCode = CodeTicketCouldNotBeCreated
+				return apiErr
+			}
+			return err
+		}
+		// the tickets come back in the order they were sent
+		for i := range result {
+			if start+i < end {
+				tickets[start+i].populateTicketFields(result[i])
+			}
+		}
+	}
+	return nil
+}
+
+// BatchCreateTickets calls Client.BatchCreateTickets on the DefaultClient with a background context
+func BatchCreateTickets(tickets []Ticket) error {
+	return DefaultClient().BatchCreateTickets(context.Background(), tickets)
+}
+
+// BatchUpdateTickets updates many existing tickets at once. Every ticket needs an ID. The tickets are sent in batches
+// of up to 100 in the same way as BatchCreateTickets.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/tickets/batch-update-tickets
+func (c *Client) BatchUpdateTickets(ctx context.Context, tickets []Ticket) error {
+	if len(tickets) == 0 {
+		return ticketMissingDataError("you must specify at least one ticket to update")
+	}
+	send := make([]ticketBatchRequest, len(tickets))
+	for i := range tickets {
+		if tickets[i].ID == 0 {
+			return ticketIDZeroError("updating")
+		}
+		props, err := tickets[i].convertTicketToProperties()
+		if err != nil {
+			return err
+		}
+		send[i] = ticketBatchRequest{
+			ObjectID:   tickets[i].ID,
+			Properties: props,
+		}
+	}
+
+	for start := 0; start < len(send); start += ticketBatchSize {
+		end := start + ticketBatchSize
+		if end > len(send) {
+			end = len(send)
+		}
+		_, err := c.prepareCall(ctx, EndpointBatchUpdateTickets, map[string]string{}, send[start:end], nil)
+		if err != nil {
+			if apiErr, apiErrOK := err.(APIError); apiErrOK {
+				apiErr.SystemCode = CodeTicketCouldNotBeUpdated
+				return apiErr
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchUpdateTickets calls Client.BatchUpdateTickets on the DefaultClient with a background context
+func BatchUpdateTickets(tickets []Ticket) error {
+	return DefaultClient().BatchUpdateTickets(context.Background(), tickets)
+}
+
+// GetTicketByID gets a single ticket by its ID. Hubspot only returns the properties that are asked for; without any,
+// those of the Ticket fields are fetched.
+//
+// API Doc: https://developers.hubspot.com/docs/methods/tickets/get_ticket_by_id
+func (c *Client) GetTicketByID(ctx context.Context, ticketID int64, properties ...string) (Ticket, error) {
+	ticket := Ticket{}
+	if ticketID == 0 {
+		return ticket, ticketIDZeroError("getting")
+	}
+	result := ticketResponse{}
+	_, err := c.prepareCall(ctx, EndpointGetTicket, map[string]string{
+		":ticketID": fmt.Sprintf("%d", ticketID),
+	}, ticketPropertyQuery(properties, false), &result)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeTicketNotFound
+				return ticket, apiErr
+			}
+			apiErr.SystemCode = CodeGeneralError
+			return ticket, apiErr
+		}
+		return ticket, err
+	}
+	ticket.populateTicketFields(result)
+	return ticket, nil
+}
+
+// GetTicketByID calls Client.GetTicketByID on the DefaultClient with a background context
+func GetTicketByID(ticketID int64, properties ...string) (Ticket, error) {
+	return DefaultClient().GetTicketByID(context.Background(), ticketID, properties...)
+}
+
+// DeleteTicketByID deletes a single ticket by its ID
+//
+// API Doc: https://developers.hubspot.com/docs/methods/tickets/delete-ticket
+func (c *Client) DeleteTicketByID(ctx context.Context, ticketID int64) error {
+	if ticketID == 0 {
+		return ticketIDZeroError("deleting")
+	}
+	_, err := c.prepareCall(ctx, EndpointDeleteTicket, map[string]string{
+		":ticketID": fmt.Sprintf("%d", ticketID),
+	}, nil, nil)
+	if err != nil {
+		if apiErr, apiErrOK := err.(APIError); apiErrOK {
+			if apiErr.HTTPCode == 404 {
+				apiErr.SystemCode = CodeTicketNotFound
+				return apiErr
+			}
+			apiErr.SystemCode = CodeTicketCouldNotBeDeleted
+			return apiErr
+		}
+	}
+	return err
+}
+
+// DeleteTicketByID calls Client.DeleteTicketByID on the DefaultClient with a background context
+func DeleteTicketByID(ticketID int64) error {
+	return DefaultClient().DeleteTicketByID(context.Background(), ticketID)
+}
+
+// TicketIterator walks through a listing of tickets, fetching pages from Hubspot as they are needed. It is used the same
+// way as a ContactIterator.
+type TicketIterator struct {
+	pager
+	page []Ticket
+}
+
+// Next advances to the next ticket. It returns false when there are no more tickets or an error occurred, which is then
+// available from Err.
+func (it *TicketIterator) Next() bool {
+	return it.next()
+}
+
+// Ticket returns the current ticket. It is only valid after a call to Next returns true.
+func (it *TicketIterator) Ticket() Ticket {
+	if i := it.current(); i >= 0 {
+		return it.page[i]
+	}
+	return Ticket{}
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *TicketIterator) Err() error {
+	return it.err
+}
+
+// Offset returns a cursor to resume the listing from with TicketListOptions.Offset. See pager.cursor for where it
+// points.
+func (it *TicketIterator) Offset() int64 {
+	return it.offset()
+}
+
+// ListTickets walks every ticket in the portal
+//
+// API Doc: https://developers.hubspot.com/docs/methods/tickets/get-all-tickets
+func (c *Client) ListTickets(ctx context.Context, opts *TicketListOptions) *TicketIterator {
+	if opts == nil {
+		opts = &TicketListOptions{}
+	}
+
+	it := &TicketIterator{}
+	it.pager = newPager(ctx, opts.Offset, func(ctx context.Context, offset int64) (int, int64, bool, error) {
+		query := ticketPropertyQuery(opts.Properties, opts.PropertiesWithHistory)
+		if offset != 0 {
+			query.Set("offset", fmt.Sprintf("%d", offset))
+		}
+
+		result := ticketListResponse{}
+		_, err := c.prepareCall(ctx, EndpointListTickets, map[string]string{}, query, &result)
+		if err != nil {
+			if apiErr, apiErrOK := err.(APIError); apiErrOK && apiErr.SystemCode != CodeResponseCouldNotBeDecoded {
+				apiErr.SystemCode = CodeTicketCouldNotBeListed
+				return 0, offset, false, apiErr
+			}
+			return 0, offset, false, err
+		}
+		it.page = make([]Ticket, len(result.Objects))
+		for i := range result.Objects {
+			it.page[i].populateTicketFields(result.Objects[i])
+		}
+		return len(it.page), result.Offset, result.HasMore, nil
+	})
+	return it
+}
+
+// ListTickets calls Client.ListTickets on the DefaultClient with a background context
+func ListTickets(opts *TicketListOptions) *TicketIterator {
+	return DefaultClient().ListTickets(context.Background(), opts)
+}
+
+// AddContactToTicket associates the contact with the ticket
+func (c *Client) AddContactToTicket(ctx context.Context, ticketID, vid int64) error {
+	return c.Associate(ctx, ticketID, vid, AssociationTicketToContact)
+}
+
+// AddContactToTicket calls Client.AddContactToTicket on the DefaultClient with a background context
+func AddContactToTicket(ticketID, vid int64) error {
+	return DefaultClient().AddContactToTicket(context.Background(), ticketID, vid)
+}
+
+// RemoveContactFromTicket removes the association between the contact and the ticket
+func (c *Client) RemoveContactFromTicket(ctx context.Context, ticketID, vid int64) error {
+	return c.RemoveAssociation(ctx, ticketID, vid, AssociationTicketToContact)
+}
+
+// RemoveContactFromTicket calls Client.RemoveContactFromTicket on the DefaultClient with a background context
+func RemoveContactFromTicket(ticketID, vid int64) error {
+	return DefaultClient().RemoveContactFromTicket(context.Background(), ticketID, vid)
+}
+
+// AddCompanyToTicket associates the company with the ticket
+func (c *Client) AddCompanyToTicket(ctx context.Context, ticketID, companyID int64) error {
+	return c.Associate(ctx, ticketID, companyID, AssociationTicketToCompany)
+}
+
+// AddCompanyToTicket calls Client.AddCompanyToTicket on the DefaultClient with a background context
+func AddCompanyToTicket(ticketID, companyID int64) error {
+	return DefaultClient().AddCompanyToTicket(context.Background(), ticketID, companyID)
+}
+
+// RemoveCompanyFromTicket removes the association between the company and the ticket
+func (c *Client) RemoveCompanyFromTicket(ctx context.Context, ticketID, companyID int64) error {
+	return c.RemoveAssociation(ctx, ticketID, companyID, AssociationTicketToCompany)
+}
+
+// RemoveCompanyFromTicket calls Client.RemoveCompanyFromTicket on the DefaultClient with a background context
+func RemoveCompanyFromTicket(ticketID, companyID int64) error {
+	return DefaultClient().RemoveCompanyFromTicket(context.Background(), ticketID, companyID)
+}
+
+// AddDealToTicket associates the deal with the ticket
+func (c *Client) AddDealToTicket(ctx context.Context, ticketID, dealID int64) error {
+	return c.Associate(ctx, ticketID, dealID, AssociationTicketToDeal)
+}
+
+// AddDealToTicket calls Client.AddDealToTicket on the DefaultClient with a background context
+func AddDealToTicket(ticketID, dealID int64) error {
+	return DefaultClient().AddDealToTicket(context.Background(), ticketID, dealID)
+}
+
+// RemoveDealFromTicket removes the association between the deal and the ticket
+func (c *Client) RemoveDealFromTicket(ctx context.Context, ticketID, dealID int64) error {
+	return c.RemoveAssociation(ctx, ticketID, dealID, AssociationTicketToDeal)
+}
+
+// RemoveDealFromTicket calls Client.RemoveDealFromTicket on the DefaultClient with a background context
+func RemoveDealFromTicket(ticketID, dealID int64) error {
+	return DefaultClient().RemoveDealFromTicket(context.Background(), ticketID, dealID)
+}
+
+// ValidateTicket checks that the ticket's stage is in its pipeline before the ticket is sent to Hubspot, in the same
+// way as ValidateDeal. The cache must be for PipelineObjectTypeTickets.
+func (cache *PipelineCache) ValidateTicket(ctx context.Context, ticket *Ticket) error {
+	if ticket.Stage == "" {
+		return nil
+	}
+	return cache.ValidateStage(ctx, ticket.Pipeline, ticket.Stage)
+}
+
+// SetProperties adds the properties of your own struct to the ticket's AdditionalProperties, using its `hubspot` struct
+// tags as described in MarshalProperties
+func (ticket *Ticket) SetProperties(v interface{}) error {
+	return appendObjectProperties(&ticket.AdditionalProperties, v)
+}
+
+// ClearProperty adds the properties to ClearProperties, so they are blanked out the next time the ticket is saved
+func (ticket *Ticket) ClearProperty(names ...string) {
+	ticket.ClearProperties = append(ticket.ClearProperties, names...)
+}
+
+// populateTicketFields fills in the ticket from Hubspot's representation of it
+func (ticket *Ticket) populateTicketFields(result ticketResponse) {
+	ticket.ID = result.ObjectID
+	ticket.Properties = newObjectProperties(result.Properties)
+	// the ticket only has string fields, which can't fail to convert
+	UnmarshalProperties(ticket.Properties.values(), ticket)
+}
+
+// convertTicketToProperties builds the properties to send for the ticket
+func (ticket *Ticket) convertTicketToProperties() ([]ObjectProperty, error) {
+	return objectPropertyList(ticket, ticket.AdditionalProperties, ticket.ClearProperties)
+}
+
+// convertTicketForCreate builds the properties to send for a new ticket, which Hubspot will not create without a
+// subject, pipeline and stage
+func (ticket *Ticket) convertTicketForCreate() ([]ObjectProperty, error) {
+	if ticket.Subject == "" || ticket.Pipeline == "" || ticket.Stage == "" {
+		return nil, ticketMissingDataError("a ticket needs a Subject, Pipeline and Stage to be created")
+	}
+	return ticket.convertTicketToProperties()
+}
+
+// ticketPropertyQuery builds the query asking for the properties of tickets
+func ticketPropertyQuery(properties []string, withHistory bool) url.Values {
+	if len(properties) == 0 {
+		properties = ticketProperties
+	}
+	propertyParam := "properties"
+	if withHistory {
+		propertyParam = "propertiesWithHistory"
+	}
+	query := url.Values{}
+	for _, property := range properties {
+		query.Add(propertyParam, property)
+	}
+	return query
+}
+
+// ticketMissingDataError is returned when a ticket call is missing something it needs
+func ticketMissingDataError(message string) APIError {
+	return APIError{
+		HTTPCode:   http.StatusBadRequest,
+		SystemCode: CodeTicketMissingData,
+		Message:    message,
+		Body:       nil,
+	}
+}
+
+// ticketIDZeroError is returned when a ticket is needed but its ID is missing
+func ticketIDZeroError(action string) APIError {
+	return APIError{
+		HTTPCode:   http.StatusBadRequest,
+		SystemCode: CodeTicketIDZero,
+		Message:    fmt.Sprintf("the ID for the ticket cannot be 0 when %s it", action),
+		Body:       nil,
+	}
+}
@@ -0,0 +1,187 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTicketResponse = `{
+	"objectType": "TICKET",
+	"portalId": 62515,
+	"objectId": 176602,
+	"isDeleted": false,
+	"properties": {
+		"subject": {"value": "Collar will not pair", "timestamp": 1577836800000, "source": "API", "sourceId": null, "versions": [
+			{"name": "subject", "value": "Collar will not pair", "timestamp": 1577836800000, "source": "API", "sourceVid": []}
+		]},
+		"hs_pipeline": {"value": "0", "timestamp": 1577836800000, "source": "API"},
+		"hs_pipeline_stage": {"value": "1", "timestamp": 1577836800000, "source": "API"},
+		"hs_ticket_priority": {"value": "HIGH", "timestamp": 1577836800000, "source": "API"}
+	}
+}`
+
+func TestTicketWrites(t *testing.T) {
+	status := http.StatusOK
+	var method, path string
+	var sent interface{}
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		sent = nil
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		switch r.URL.Path {
+		case "/crm-objects/v1/objects/tickets/batch-create":
+			results := []string{}
+			for i := range sent.([]interface{}) {
+				results = append(results, fmt.Sprintf(`{"objectId": %d}`, 1000+i))
+			}
+			fmt.Fprintf(w, "[%s]", strings.Join(results, ","))
+		case "/crm-objects/v1/objects/tickets/batch-update":
+		default:
+			fmt.Fprint(w, testTicketResponse)
+		}
+	})
+	defer server.Close()
+
+	err := client.CreateTicket(context.Background(), &Ticket{Subject: "Collar will not pair"})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeTicketMissingData, err.(APIError).SystemCode)
+	err = client.UpdateTicket(context.Background(), &Ticket{Stage: "2"})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeTicketIDZero, err.(APIError).SystemCode)
+	assert.Equal(t, "", path)
+
+	ticket := Ticket{Subject: "Collar will not pair", Pipeline: "0", Stage: "1", Priority: TicketPriorityHigh}
+	require.Nil(t, client.CreateTicket(context.Background(), &ticket))
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/crm-objects/v1/objects/tickets", path)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "subject", "value": "Collar will not pair"},
+		map[string]interface{}{"name": "hs_pipeline", "value": "0"},
+		map[string]interface{}{"name": "hs_pipeline_stage", "value": "1"},
+		map[string]interface{}{"name": "hs_ticket_priority", "value": "HIGH"},
+	}, sent)
+	assert.Equal(t, int64(176602), ticket.ID)
+	require.Len(t, ticket.Properties.Versions("subject"), 1)
+
+	update := Ticket{ID: 176602, Stage: "4"}
+	update.ClearProperty("hs_ticket_priority")
+	require.Nil(t, client.UpdateTicket(context.Background(), &update))
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/crm-objects/v1/objects/tickets/176602", path)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "hs_pipeline_stage", "value": "4"},
+		map[string]interface{}{"name": "hs_ticket_priority", "value": ""},
+	}, sent)
+	assert.Equal(t, "Collar will not pair", update.Subject)
+
+	tickets := []Ticket{}
+	for i := 0; i < 150; i++ {
+		tickets = append(tickets, Ticket{Subject: fmt.Sprintf("Issue %d", i), Pipeline: "0", Stage: "1"})
+	}
+	require.Nil(t, client.BatchCreateTickets(context.Background(), tickets))
+	// the last batch has what was left over, and every ticket gets its ID
+	require.Len(t, sent, 50)
+	assert.Equal(t, int64(1000), tickets[0].ID)
+	assert.Equal(t, int64(1049), tickets[149].ID)
+	err = client.BatchCreateTickets(context.Background(), []Ticket{{Subject: "No pipeline"}})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeTicketMissingData, err.(APIError).SystemCode)
+
+	require.Nil(t, client.BatchUpdateTickets(context.Background(), tickets[:2]))
+	assert.Equal(t, "/crm-objects/v1/objects/tickets/batch-update", path)
+	assert.Equal(t, float64(1001), sent.([]interface{})[1].(map[string]interface{})["objectId"])
+	err = client.BatchUpdateTickets(context.Background(), []Ticket{{Subject: "No ID"}})
+	require.NotNil(t, err)
+	assert.Equal(t, CodeTicketIDZero, err.(APIError).SystemCode)
+
+	status = http.StatusNotFound
+	err = client.UpdateTicket(context.Background(), &update)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeTicketNotFound, err.(APIError).SystemCode)
+	err = client.DeleteTicketByID(context.Background(), 176602)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeTicketNotFound, err.(APIError).SystemCode)
+	assert.Equal(t, http.MethodDelete, method)
+
+	status = http.StatusBadRequest
+	err = client.CreateTicket(context.Background(), &ticket)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeTicketCouldNotBeCreated, err.(APIError).SystemCode)
+}
+
+func TestTicketLookups(t *testing.T) {
+	queries := []url.Values{}
+	var path string
+	client, server := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/crm-objects/v1/objects/tickets/176602":
+			fmt.Fprint(w, testTicketResponse)
+		case "/crm-objects/v1/objects/tickets/paged":
+			if r.URL.Query().Get("offset") == "" {
+				fmt.Fprintf(w, `{"objects": [%s, {"objectId": 2}], "hasMore": true, "offset": 2}`, testTicketResponse)
+				return
+			}
+			fmt.Fprint(w, `{"objects": [{"objectId": 3}], "hasMore": false, "offset": 3}`)
+		case "/crm-associations/v1/associations":
+			w.WriteHeader(http.StatusNoContent)
+		case "/crm/v3/pipelines/tickets":
+			fmt.Fprint(w, `{"results": [{"id": "0", "label": "Support Pipeline", "stages": [{"id": "1", "label": "New"}, {"id": "4", "label": "Closed"}]}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	ticket, err := client.GetTicketByID(context.Background(), 176602)
+	require.Nil(t, err)
+	assert.Equal(t, TicketPriorityHigh, ticket.Priority)
+	assert.Equal(t, ticketProperties, queries[0]["properties"])
+	_, err = client.GetTicketByID(context.Background(), 176602, "subject")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"subject"}, queries[1]["properties"])
+	_, err = client.GetTicketByID(context.Background(), 1)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeTicketNotFound, err.(APIError).SystemCode)
+
+	queries = []url.Values{}
+	it := client.ListTickets(context.Background(), &TicketListOptions{Properties: []string{"subject"}, PropertiesWithHistory: true})
+	ids := []int64{}
+	for it.Next() {
+		ids = append(ids, it.Ticket().ID)
+	}
+	require.Nil(t, it.Err())
+	assert.Equal(t, []int64{176602, 2, 3}, ids)
+	assert.Equal(t, int64(3), it.Offset())
+	assert.Equal(t, []string{"subject"}, queries[0]["propertiesWithHistory"])
+	assert.Equal(t, "2", queries[1].Get("offset"))
+
+	require.Nil(t, client.AddContactToTicket(context.Background(), 176602, 27))
+	require.Nil(t, client.AddCompanyToTicket(context.Background(), 176602, 10444744))
+	require.Nil(t, client.AddDealToTicket(context.Background(), 176602, 151088))
+	assert.Equal(t, "/crm-associations/v1/associations", path)
+	err = client.RemoveDealFromTicket(context.Background(), 176602, 151088)
+	require.NotNil(t, err)
+	assert.Equal(t, CodeAssociationCouldNotBeRemoved, err.(APIError).SystemCode)
+
+	pipelines := client.NewPipelineCache(PipelineObjectTypeTickets, 0)
+	stage, err := pipelines.StageID(context.Background(), "0", "closed")
+	require.Nil(t, err)
+	require.Nil(t, pipelines.ValidateTicket(context.Background(), &Ticket{Pipeline: "0", Stage: stage}))
+	err = pipelines.ValidateTicket(context.Background(), &Ticket{Pipeline: "0", Stage: "7"})
+	require.NotNil(t, err)
+	assert.Equal(t, CodePipelineStageNotFound, err.(APIError).SystemCode)
+}
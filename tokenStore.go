@@ -0,0 +1,186 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenStore persists oAuth tokens per portal, so that tokens survive restarts and several replicas of a service can
+// share one token instead of each refreshing their own. Implement it to back tokens with Redis, Postgres, etc.
+type TokenStore interface {
+	// GetToken returns the token stored for the portal, or nil if there is none
+	GetToken(ctx context.Context, portalID string) (*OAuthToken, error)
+	// SaveToken stores the token for the portal, replacing any previous one
+	SaveToken(ctx context.Context, portalID string, token OAuthToken) error
+	// Lock blocks until it holds an exclusive lock on the portal's token, or the context is done. The client holds the
+	// lock while it refreshes the token, so only one refresh is in flight across every process sharing the store. The
+	// returned func releases the lock.
+	Lock(ctx context.Context, portalID string) (unlock func(), err error)
+}
+
+// MemoryTokenStore is a TokenStore that keeps tokens in memory. It lets several clients in the same process share
+// tokens, but nothing survives a restart.
+type MemoryTokenStore struct {
+	mutex  sync.Mutex
+	tokens map[string]OAuthToken
+	locks  map[string]chan struct{}
+}
+
+// NewMemoryTokenStore creates an empty MemoryTokenStore
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		tokens: map[string]OAuthToken{},
+		locks:  map[string]chan struct{}{},
+	}
+}
+
+// GetToken returns the token stored for the portal, or nil if there is none
+func (s *MemoryTokenStore) GetToken(ctx context.Context, portalID string) (*OAuthToken, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	token, found := s.tokens[portalID]
+	if !found {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+// SaveToken stores the token for the portal
+func (s *MemoryTokenStore) SaveToken(ctx context.Context, portalID string, token OAuthToken) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tokens[portalID] = token
+	return nil
+}
+
+// Lock takes the lock for the portal, waiting for it to be released if another caller holds it
+func (s *MemoryTokenStore) Lock(ctx context.Context, portalID string) (func(), error) {
+	s.mutex.Lock()
+	lock, found := s.locks[portalID]
+	if !found {
+		lock = make(chan struct{}, 1)
+		s.locks[portalID] = lock
+	}
+	s.mutex.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// FileTokenStore is a TokenStore that keeps one JSON file per portal in a directory. Locks are lock files next to the
+// tokens, so processes on the same machine, or sharing the directory, can coordinate their refreshes.
+type FileTokenStore struct {
+	Directory string
+	// StaleLockAge is how old a lock file can get before it is assumed to have been left behind by a crashed process
+	// and is removed; defaults to one minute
+	StaleLockAge time.Duration
+	// PollInterval is how often a held lock is checked while waiting for it; defaults to 50 milliseconds
+	PollInterval time.Duration
+}
+
+// NewFileTokenStore creates a FileTokenStore in the passed in directory, creating the directory if needed
+func NewFileTokenStore(directory string) (*FileTokenStore, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, err
+	}
+	return &FileTokenStore{
+		Directory:    directory,
+		StaleLockAge: time.Minute,
+		PollInterval: 50 * time.Millisecond,
+	}, nil
+}
+
+// path builds the path of a file for the portal. Any character of the portal ID that is not a letter, digit, dash or
+// underscore is escaped as %XX, so the ID cannot escape the directory and no two IDs share a file. The empty ID gets
+// "%default", which no escaped ID can be, as escapes are upper case.
+func (s *FileTokenStore) path(portalID, extension string) string {
+	name := "%default"
+	if portalID != "" {
+		var escaped strings.Builder
+		for i := 0; i < len(portalID); i++ {
+			c := portalID[i]
+			if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_' {
+				escaped.WriteByte(c)
+				continue
+			}
+			fmt.Fprintf(&escaped, "%%%02X", c)
+		}
+		name = escaped.String()
+	}
+	return filepath.Join(s.Directory, fmt.Sprintf("%s.%s", name, extension))
+}
+
+// GetToken reads the token stored for the portal, or nil if there is none
+func (s *FileTokenStore) GetToken(ctx context.Context, portalID string) (*OAuthToken, error) {
+	contents, err := ioutil.ReadFile(s.path(portalID, "json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	token := OAuthToken{}
+	if err := json.Unmarshal(contents, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// SaveToken writes the token for the portal. The file is written to a temporary file first and moved into place, so
+// readers never see a partial token.
+func (s *FileTokenStore) SaveToken(ctx context.Context, portalID string, token OAuthToken) error {
+	contents, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(s.Directory, "token-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(portalID, "json"))
+}
+
+// Lock creates the lock file for the portal, polling until it can or the context is done
+func (s *FileTokenStore) Lock(ctx context.Context, portalID string) (func(), error) {
+	lockPath := s.path(portalID, "lock")
+	pollInterval := s.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = 50 * time.Millisecond
+	}
+	for {
+		lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			lockFile.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && s.StaleLockAge > 0 && time.Since(info.ModTime()) > s.StaleLockAge {
+			os.Remove(lockPath)
+			continue
+		}
+		if err := sleepContext(ctx, pollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package hubspot
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTokenStoreLocking(t *testing.T, store TokenStore) {
+	unlock, err := store.Lock(context.Background(), "62515")
+	require.Nil(t, err)
+
+	// the lock is exclusive per portal
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = store.Lock(ctx, "62515")
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	otherUnlock, err := store.Lock(context.Background(), "99999")
+	require.Nil(t, err)
+	otherUnlock()
+
+	unlock()
+	unlock, err = store.Lock(context.Background(), "62515")
+	require.Nil(t, err)
+	unlock()
+}
+
+func testTokenStoreSaving(t *testing.T, store TokenStore) {
+	token, err := store.GetToken(context.Background(), "62515")
+	require.Nil(t, err)
+	assert.Nil(t, token)
+
+	expiresAt := time.Now().Add(time.Hour).Round(time.Second)
+	err = store.SaveToken(context.Background(), "62515", OAuthToken{
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		ExpiresIn:    3600,
+		ExpiresAt:    expiresAt,
+	})
+	require.Nil(t, err)
+
+	token, err = store.GetToken(context.Background(), "62515")
+	require.Nil(t, err)
+	require.NotNil(t, token)
+	assert.Equal(t, "access-token", token.AccessToken)
+	assert.Equal(t, "refresh-token", token.RefreshToken)
+	assert.True(t, expiresAt.Equal(token.ExpiresAt))
+}
+
+func TestMemoryTokenStore(t *testing.T) {
+	testTokenStoreSaving(t, NewMemoryTokenStore())
+	testTokenStoreLocking(t, NewMemoryTokenStore())
+}
+
+func TestFileTokenStore(t *testing.T) {
+	directory, err := ioutil.TempDir("", "hubspot-tokens")
+	require.Nil(t, err)
+	defer os.RemoveAll(directory)
+
+	store, err := NewFileTokenStore(directory)
+	require.Nil(t, err)
+	testTokenStoreSaving(t, store)
+	testTokenStoreLocking(t, store)
+
+	// portal IDs cannot escape the directory, and IDs that differ only in unsafe characters don't share a file
+	assert.Equal(t, filepath.Join(directory, "%2E%2E%2Fetc%2Fpasswd.json"), store.path("../etc/passwd", "json"))
+	assert.Equal(t, filepath.Join(directory, "a%2Fb.json"), store.path("a/b", "json"))
+	assert.Equal(t, filepath.Join(directory, "a_b.json"), store.path("a_b", "json"))
+	assert.Equal(t, filepath.Join(directory, "%25default.json"), store.path("%default", "json"))
+	assert.Equal(t, filepath.Join(directory, "%default.json"), store.path("", "json"))
+	require.Nil(t, store.SaveToken(context.Background(), "a/b", OAuthToken{AccessToken: "slash"}))
+	require.Nil(t, store.SaveToken(context.Background(), "a_b", OAuthToken{AccessToken: "underscore"}))
+	token, err := store.GetToken(context.Background(), "a/b")
+	require.Nil(t, err)
+	assert.Equal(t, "slash", token.AccessToken)
+
+	// a lock left behind by a crashed process is cleaned up
+	store.StaleLockAge = time.Millisecond
+	_, err = store.Lock(context.Background(), "stale")
+	require.Nil(t, err)
+	time.Sleep(10 * time.Millisecond)
+	unlock, err := store.Lock(context.Background(), "stale")
+	require.Nil(t, err)
+	unlock()
+}
+
+func TestClientsShareTokenStore(t *testing.T) {
+	tokenServer := &oAuthTestServer{}
+	store := NewMemoryTokenStore()
+
+	// two replicas of the same service, talking to the same portal
+	clients := []*Client{}
+	for i := 0; i < 2; i++ {
+		client, server := newTestClient(tokenServer.handle)
+		defer server.Close()
+		client.Config().HubSpotOAuthRefreshToken = "refresh-token"
+		client.Config().HubspotPortalID = "62515"
+		client.Config().TokenStore = store
+		clients = append(clients, client)
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+			assert.Nil(t, client.CreateOrUpdateEvent(context.Background(), &Event{ObjectID: 1, EventTypeID: 2}))
+		}(clients[i%2])
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenServer.refreshes))
+
+	stored, err := store.GetToken(context.Background(), "62515")
+	require.Nil(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "access-token-1", stored.AccessToken)
+
+	// a restarted client picks up the stored token instead of refreshing
+	restarted, server := newTestClient(tokenServer.handle)
+	defer server.Close()
+	restarted.Config().HubspotPortalID = "62515"
+	restarted.Config().TokenStore = store
+	assert.Nil(t, restarted.CreateOrUpdateEvent(context.Background(), &Event{ObjectID: 1, EventTypeID: 2}))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenServer.refreshes))
+}